@@ -21,7 +21,7 @@ func Assert(namespace string, timeout int, assertFiles ...string) error {
 	var objects []client.Object
 
 	for _, file := range assertFiles {
-		o, err := step.ObjectsFromPath(file, "")
+		o, err := step.ObjectsFromPath(file, "", nil)
 		if err != nil {
 			return err
 		}
@@ -61,7 +61,7 @@ func Errors(namespace string, timeout int, errorFiles ...string) error {
 	var objects []client.Object
 
 	for _, file := range errorFiles {
-		o, err := step.ObjectsFromPath(file, "")
+		o, err := step.ObjectsFromPath(file, "", nil)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,73 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// envtestProvider starts a mocked control plane with controller-runtime's envtest.
+type envtestProvider struct {
+	attachOutput bool
+	options      Options
+
+	env    *envtest.Environment
+	config *rest.Config
+}
+
+func newEnvtestProvider(suite *harness.TestSuite, options Options) (Provider, error) {
+	return &envtestProvider{attachOutput: suite.AttachControlPlaneOutput, options: options}, nil
+}
+
+// Start starts a Kubernetes API server and etcd.
+func (p *envtestProvider) Start(_ context.Context) error {
+	started := time.Now()
+
+	testenv, err := kubernetes.StartTestEnvironment(p.attachOutput)
+	if err != nil {
+		return err
+	}
+
+	p.options.Logger.Logf("started test environment (kube-apiserver and etcd) in %v, with following options:\n%s",
+		time.Since(started),
+		strings.Join(testenv.Environment.ControlPlane.GetAPIServer().Configure().AsStrings(nil), "\n"))
+	p.env = testenv.Environment
+	p.config = testenv.Config
+	return nil
+}
+
+func (p *envtestProvider) Kubeconfig() (*rest.Config, error) {
+	if p.config == nil {
+		return nil, errors.New("the mocked control plane is not started")
+	}
+	return p.config, nil
+}
+
+// LoadImages does nothing, as the mocked control plane has no nodes to run containers.
+func (p *envtestProvider) LoadImages(_ context.Context, images []string) error {
+	if len(images) > 0 {
+		p.options.Logger.Warnf("the mocked control plane has no nodes, not loading images %v", images)
+	}
+	return nil
+}
+
+// CollectLogs does nothing, the output of the control plane is attached to the output of the tests on demand.
+func (p *envtestProvider) CollectLogs(_ context.Context, _ string) error {
+	return nil
+}
+
+func (p *envtestProvider) Stop(_ context.Context) error {
+	if p.env == nil {
+		return nil
+	}
+	err := p.env.Stop()
+	p.env = nil
+	return err
+}
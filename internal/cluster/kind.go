@@ -0,0 +1,159 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	docker "github.com/moby/moby/client"
+	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	kindConfig "sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	"github.com/kudobuilder/kuttl/internal/kind"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// kindProvider starts a KIND cluster. An existing cluster with the same context is not taken over.
+type kindProvider struct {
+	context   string
+	config    string
+	nodeCache bool
+	options   Options
+
+	kind *kind.Kind
+	// Whether the cluster was created by the provider, and should be deleted.
+	created bool
+}
+
+func newKindProvider(suite *harness.TestSuite, options Options) (Provider, error) {
+	p := &kindProvider{
+		context:   suite.KINDContext,
+		config:    suite.KINDConfig,
+		nodeCache: suite.KINDNodeCache,
+		options:   options,
+	}
+	if p.context == "" {
+		p.context = harness.DefaultKINDContext
+	}
+	k := kind.NewKind(p.context, p.kubeconfigPath(), options.Logger)
+	p.kind = &k
+	return p, nil
+}
+
+func (p *kindProvider) kubeconfigPath() string {
+	return filepath.Join(p.options.TempDir, "kubeconfig")
+}
+
+// Start creates the KIND cluster, unless a cluster is already running for the context.
+func (p *kindProvider) Start(_ context.Context) error {
+	if p.kind.IsRunning() {
+		// we don't take over an existing kind cluster for --start-kind
+		// which means we do not stop that cluster.  User will either need to switch to existing cluster or stop it.
+		return errors.New("KIND is already running, unable to start")
+	}
+
+	kindCfg := &kindConfig.Cluster{}
+	if p.config != "" {
+		p.options.Logger.Logf("Loading KIND config from %s", p.config)
+		var err error
+		kindCfg, err = p.loadKindConfig(p.config)
+		if err != nil {
+			return err
+		}
+	}
+
+	dockerClient, err := p.options.Docker()
+	if err != nil {
+		return err
+	}
+	p.addNodeCaches(dockerClient, kindCfg)
+
+	p.options.Logger.Log("Starting KIND cluster")
+	p.created = true
+	return p.kind.Run(kindCfg)
+}
+
+func (p *kindProvider) Kubeconfig() (*rest.Config, error) {
+	return clientcmd.BuildConfigFromFlags("", p.kubeconfigPath())
+}
+
+// LoadImages loads the named Docker images into the nodes of the cluster.
+func (p *kindProvider) LoadImages(_ context.Context, images []string) error {
+	dockerClient, err := p.options.Docker()
+	if err != nil {
+		return err
+	}
+	return p.kind.AddContainers(dockerClient, images, p.options.Logger)
+}
+
+func (p *kindProvider) CollectLogs(_ context.Context, dir string) error {
+	if !p.created {
+		return nil
+	}
+	p.options.Logger.Logf("collecting cluster logs to %s", dir)
+	return p.kind.CollectLogs(dir)
+}
+
+func (p *kindProvider) Stop(_ context.Context) error {
+	if !p.created {
+		return nil
+	}
+	p.created = false
+	return p.kind.Stop()
+}
+
+// addNodeCaches mounts the containerd directory of each node into a Docker volume, if the node cache is enabled,
+// so that the images pulled by the nodes are kept across test runs.
+func (p *kindProvider) addNodeCaches(dockerClient testutils.DockerClient, kindCfg *kindConfig.Cluster) {
+	if !p.nodeCache {
+		return
+	}
+
+	// add a default node if there are none specified.
+	if len(kindCfg.Nodes) == 0 {
+		kindCfg.Nodes = append(kindCfg.Nodes, kindConfig.Node{})
+	}
+
+	for index := range kindCfg.Nodes {
+		result, err := dockerClient.VolumeCreate(context.TODO(), docker.VolumeCreateOptions{
+			Driver: "local",
+			Name:   fmt.Sprintf("%s-%d", p.context, index),
+		})
+		if err != nil {
+			p.options.Logger.Warnf("error creating volume for node: %v", err)
+			continue
+		}
+
+		p.options.Logger.Log("node mount point", result.Volume.Mountpoint)
+		kindCfg.Nodes[index].ExtraMounts = append(kindCfg.Nodes[index].ExtraMounts, kindConfig.Mount{
+			ContainerPath: "/var/lib/containerd",
+			HostPath:      result.Volume.Mountpoint,
+		})
+	}
+}
+
+func (p *kindProvider) loadKindConfig(path string) (*kindConfig.Cluster, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &kindConfig.Cluster{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.SetStrict(true)
+
+	if err := decoder.Decode(cluster); err != nil {
+		return nil, err
+	}
+	if !kind.IsMinVersion(cluster.APIVersion) {
+		p.options.Logger.Warnf("%q in %s is not a supported version.", cluster.APIVersion, path)
+	}
+	return cluster, nil
+}
@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/assert"
+	kindConfig "sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+type dockerMock struct {
+	ImageWriter *io.PipeWriter
+	imageReader *io.PipeReader
+}
+
+func newDockerMock() *dockerMock {
+	reader, writer := io.Pipe()
+
+	return &dockerMock{
+		ImageWriter: writer,
+		imageReader: reader,
+	}
+}
+
+func (d *dockerMock) VolumeCreate(_ context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+	return client.VolumeCreateResult{
+		Volume: volume.Volume{
+			Mountpoint: fmt.Sprintf("/var/lib/docker/data/%s", options.Name),
+		},
+	}, nil
+}
+
+func (d *dockerMock) ImageSave(context.Context, []string, ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+	return d.imageReader, nil
+}
+
+func TestAddNodeCaches(t *testing.T) {
+	docker := newDockerMock()
+	p := &kindProvider{
+		context: harness.DefaultKINDContext,
+		options: Options{Logger: testutils.NewTestLogger(t, "")},
+	}
+
+	kindCfg := &kindConfig.Cluster{}
+	p.addNodeCaches(docker, kindCfg)
+	assert.Nil(t, kindCfg.Nodes)
+
+	p.nodeCache = true
+	p.addNodeCaches(docker, kindCfg)
+	assert.NotNil(t, kindCfg.Nodes)
+	assert.Equal(t, 1, len(kindCfg.Nodes))
+	assert.NotNil(t, kindCfg.Nodes[0].ExtraMounts)
+	assert.Equal(t, 1, len(kindCfg.Nodes[0].ExtraMounts))
+	assert.Equal(t, "/var/lib/containerd", kindCfg.Nodes[0].ExtraMounts[0].ContainerPath)
+	assert.Equal(t, "/var/lib/docker/data/kind-0", kindCfg.Nodes[0].ExtraMounts[0].HostPath)
+
+	kindCfg = &kindConfig.Cluster{
+		Nodes: []kindConfig.Node{
+			{},
+			{},
+		},
+	}
+
+	p.addNodeCaches(docker, kindCfg)
+	assert.NotNil(t, kindCfg.Nodes)
+	assert.Equal(t, 2, len(kindCfg.Nodes))
+	assert.NotNil(t, kindCfg.Nodes[0].ExtraMounts)
+	assert.Equal(t, 1, len(kindCfg.Nodes[0].ExtraMounts))
+	assert.Equal(t, "/var/lib/containerd", kindCfg.Nodes[0].ExtraMounts[0].ContainerPath)
+	assert.Equal(t, "/var/lib/docker/data/kind-0", kindCfg.Nodes[0].ExtraMounts[0].HostPath)
+	assert.Equal(t, "/var/lib/docker/data/kind-1", kindCfg.Nodes[1].ExtraMounts[0].HostPath)
+}
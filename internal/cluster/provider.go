@@ -0,0 +1,99 @@
+// Package cluster provides the providers of the Kubernetes clusters the tests run against.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/rest"
+
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// The names of the built-in providers.
+const (
+	// Envtest starts a mocked control plane: a Kubernetes API server and etcd, without nodes or controllers.
+	Envtest = "envtest"
+	// Kind starts a KIND cluster, running Kubernetes in Docker.
+	Kind = "kind"
+	// Script runs the user-defined commands of the ScriptCluster of the test suite.
+	Script = "script"
+)
+
+// Provider provides the cluster the tests run against, from its start to its deletion once the tests are done.
+type Provider interface {
+	// Start starts the cluster, and waits until its API server is available.
+	Start(ctx context.Context) error
+	// Kubeconfig returns the configuration to connect to the started cluster.
+	Kubeconfig() (*rest.Config, error)
+	// LoadImages loads the named container images into the nodes of the started cluster.
+	LoadImages(ctx context.Context, images []string) error
+	// CollectLogs writes the logs of the cluster to dir, if it has any, and logs that it does.
+	CollectLogs(ctx context.Context, dir string) error
+	// Stop deletes the cluster, if it was started by the provider.
+	Stop(ctx context.Context) error
+}
+
+// Options are the options of a provider which do not come from the test suite.
+type Options struct {
+	// Logger of the test harness.
+	Logger testutils.Logger
+	// TempDir is a directory for the files of the provider, e.g. the kubeconfig of the cluster.
+	// It is removed once the tests are done, unless the cluster is not deleted.
+	TempDir string
+	// Docker returns the client of the Docker daemon, for the providers of clusters running in Docker.
+	Docker func() (testutils.DockerClient, error)
+}
+
+// Factory creates a provider configured by the test suite.
+type Factory func(suite *harness.TestSuite, options Options) (Provider, error)
+
+var (
+	factoriesLock sync.RWMutex
+	factories     = map[string]Factory{
+		Envtest: newEnvtestProvider,
+		Kind:    newKindProvider,
+		Script:  newScriptProvider,
+	}
+)
+
+// Register makes a provider available by name, for the clusterProvider field of the test suite.
+// It panics if a provider is already registered with the same name.
+func Register(name string, factory Factory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	if factory == nil {
+		panic("cluster: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic(fmt.Sprintf("cluster: Register called twice for provider %q", name))
+	}
+	factories[name] = factory
+}
+
+// Names returns the sorted names of the registered providers.
+func Names() []string {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the named provider, configured by the test suite.
+func New(name string, suite *harness.TestSuite, options Options) (Provider, error) {
+	factoriesLock.RLock()
+	factory, ok := factories[name]
+	factoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown cluster provider %q, must be one of %s", name, strings.Join(Names(), ", "))
+	}
+	return factory(suite, options)
+}
@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+type staticProvider struct {
+	host string
+}
+
+func (p *staticProvider) Start(context.Context) error { return nil }
+
+func (p *staticProvider) Kubeconfig() (*rest.Config, error) { return &rest.Config{Host: p.host}, nil }
+
+func (p *staticProvider) LoadImages(context.Context, []string) error { return nil }
+
+func (p *staticProvider) CollectLogs(context.Context, string) error { return nil }
+
+func (p *staticProvider) Stop(context.Context) error { return nil }
+
+func TestRegister(t *testing.T) {
+	Register("static", func(suite *harness.TestSuite, _ Options) (Provider, error) {
+		return &staticProvider{host: "https://" + suite.KINDContext}, nil
+	})
+	t.Cleanup(func() {
+		factoriesLock.Lock()
+		defer factoriesLock.Unlock()
+		delete(factories, "static")
+	})
+	assert.Equal(t, []string{"envtest", "kind", "script", "static"}, Names())
+
+	provider, err := New("static", &harness.TestSuite{KINDContext: "cluster.example.com"}, Options{})
+	require.NoError(t, err)
+	cfg, err := provider.Kubeconfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://cluster.example.com", cfg.Host)
+
+	assert.PanicsWithValue(t, `cluster: Register called twice for provider "kind"`, func() {
+		Register(Kind, newKindProvider)
+	})
+	_, err = New("minikube", &harness.TestSuite{}, Options{})
+	assert.EqualError(t, err, `unknown cluster provider "minikube", must be one of envtest, kind, script, static`)
+}
+
+func TestEnvtestProviderNotStarted(t *testing.T) {
+	provider, err := New(Envtest, &harness.TestSuite{}, Options{})
+	require.NoError(t, err)
+	_, err = provider.Kubeconfig()
+	assert.EqualError(t, err, "the mocked control plane is not started")
+	assert.NoError(t, provider.Stop(t.Context()))
+}
@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// scriptProvider manages a cluster with the user-defined commands of the ScriptCluster of the test suite.
+type scriptProvider struct {
+	commands harness.ScriptCluster
+	options  Options
+
+	config *rest.Config
+	// Whether the create command was run, and the cluster should be deleted.
+	created bool
+}
+
+func newScriptProvider(suite *harness.TestSuite, options Options) (Provider, error) {
+	commands := suite.ScriptCluster
+	if !isCommandSet(commands.Create) || !isCommandSet(commands.Kubeconfig) {
+		return nil, errors.New("the script cluster provider requires the create and kubeconfig commands of scriptCluster")
+	}
+	for _, cmd := range []harness.Command{commands.Create, commands.Kubeconfig, commands.LoadImage, commands.CollectLogs, commands.Delete} {
+		if cmd.Background {
+			return nil, fmt.Errorf("command %q of scriptCluster can not run in the background", cmd.String())
+		}
+	}
+	return &scriptProvider{commands: commands, options: options}, nil
+}
+
+func isCommandSet(cmd harness.Command) bool {
+	return cmd.Command != "" || cmd.Script != ""
+}
+
+func (p *scriptProvider) kubeconfigPath() string {
+	return filepath.Join(p.options.TempDir, "kubeconfig")
+}
+
+// run runs cmd with the kubeconfig of the cluster and the environment variables env, logging its output.
+// If stdout is not nil, the standard output of the command is written to it instead.
+func (p *scriptProvider) run(ctx context.Context, cmd harness.Command, stdout io.Writer, env testutils.Variables) error {
+	output := testutils.CommandOutput(p.options.Logger)
+	if stdout == nil {
+		stdout = output
+	} else {
+		// The output is needed even if the command skips logging it.
+		cmd.SkipLogOutput = false
+	}
+	_, err := testutils.RunCommand(ctx, "", cmd, "", stdout, output, p.options.Logger, 0, p.kubeconfigPath(), env)
+	p.options.Logger.Flush()
+	return err
+}
+
+// Start runs the create command, then writes the output of the kubeconfig command to the kubeconfig of the cluster.
+func (p *scriptProvider) Start(ctx context.Context) error {
+	p.options.Logger.Log("Creating cluster with the create command of scriptCluster")
+	p.created = true
+	if err := p.run(ctx, p.commands.Create, nil, nil); err != nil {
+		return err
+	}
+
+	var kubeconfig bytes.Buffer
+	if err := p.run(ctx, p.commands.Kubeconfig, &kubeconfig, nil); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p.kubeconfigPath(), kubeconfig.Bytes(), 0600); err != nil {
+		return err
+	}
+	config, err := clientcmd.BuildConfigFromFlags("", p.kubeconfigPath())
+	if err != nil {
+		return fmt.Errorf("loading the output of the kubeconfig command of scriptCluster: %w", err)
+	}
+	p.config = config
+	return nil
+}
+
+func (p *scriptProvider) Kubeconfig() (*rest.Config, error) {
+	if p.config == nil {
+		return nil, errors.New("the cluster of the script provider is not started")
+	}
+	return p.config, nil
+}
+
+// LoadImages runs the loadImage command for each image.
+func (p *scriptProvider) LoadImages(ctx context.Context, images []string) error {
+	if !isCommandSet(p.commands.LoadImage) {
+		p.options.Logger.Warnf("scriptCluster has no loadImage command, not loading images %v", images)
+		return nil
+	}
+	for _, image := range images {
+		if err := p.run(ctx, p.commands.LoadImage, nil, testutils.Variables{"IMAGE": image}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CollectLogs runs the collectLogs command, if the provider created the cluster.
+func (p *scriptProvider) CollectLogs(ctx context.Context, dir string) error {
+	if !p.created || !isCommandSet(p.commands.CollectLogs) {
+		return nil
+	}
+	p.options.Logger.Logf("collecting cluster logs to %s", dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return p.run(ctx, p.commands.CollectLogs, nil, testutils.Variables{"LOGS_DIR": dir})
+}
+
+// Stop runs the delete command, if the provider created the cluster.
+func (p *scriptProvider) Stop(ctx context.Context) error {
+	if !p.created {
+		return nil
+	}
+	p.created = false
+	if !isCommandSet(p.commands.Delete) {
+		p.options.Logger.Warnf("scriptCluster has no delete command, not deleting the cluster")
+		return nil
+	}
+	return p.run(ctx, p.commands.Delete, nil, nil)
+}
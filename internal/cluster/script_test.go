@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: script
+  cluster:
+    server: https://script.example.com:6443
+contexts:
+- name: script
+  context:
+    cluster: script
+    user: script
+current-context: script
+users:
+- name: script
+  user:
+    token: secret
+`
+
+func TestScriptProvider(t *testing.T) {
+	dir := t.TempDir()
+	tempDir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubeconfig.yaml"), []byte(testKubeconfig), 0600))
+
+	suite := &harness.TestSuite{ScriptCluster: harness.ScriptCluster{
+		Create:      harness.Command{Script: "echo create >> " + calls},
+		Kubeconfig:  harness.Command{Command: "cat " + filepath.Join(dir, "kubeconfig.yaml")},
+		LoadImage:   harness.Command{Script: `echo "load $IMAGE" >> ` + calls},
+		CollectLogs: harness.Command{Script: `echo logs > "$LOGS_DIR/cluster.log"`},
+		Delete:      harness.Command{Script: `echo "delete $KUBECONFIG" >> ` + calls},
+	}}
+	provider, err := New(Script, suite, Options{Logger: testutils.NewTestLogger(t, ""), TempDir: tempDir})
+	require.NoError(t, err)
+
+	_, err = provider.Kubeconfig()
+	assert.EqualError(t, err, "the cluster of the script provider is not started")
+
+	require.NoError(t, provider.Start(t.Context()))
+	cfg, err := provider.Kubeconfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://script.example.com:6443", cfg.Host)
+	assert.Equal(t, "secret", cfg.BearerToken)
+
+	require.NoError(t, provider.LoadImages(t.Context(), []string{"nginx:1.27", "busybox"}))
+	logDir := filepath.Join(dir, "script-logs")
+	require.NoError(t, provider.CollectLogs(t.Context(), logDir))
+	require.NoError(t, provider.Stop(t.Context()))
+	require.NoError(t, provider.Stop(t.Context()))
+
+	logs, err := os.ReadFile(filepath.Join(logDir, "cluster.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "logs\n", string(logs))
+	executed, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Equal(t, "create\nload nginx:1.27\nload busybox\ndelete "+filepath.Join(tempDir, "kubeconfig")+"\n", string(executed))
+}
+
+func TestScriptProviderCommandsRequired(t *testing.T) {
+	_, err := New(Script, &harness.TestSuite{ScriptCluster: harness.ScriptCluster{
+		Create: harness.Command{Command: "create-cluster"},
+	}}, Options{})
+	assert.EqualError(t, err, "the script cluster provider requires the create and kubeconfig commands of scriptCluster")
+
+	_, err = New(Script, &harness.TestSuite{ScriptCluster: harness.ScriptCluster{
+		Create:     harness.Command{Command: "create-cluster", Background: true},
+		Kubeconfig: harness.Command{Command: "get-kubeconfig"},
+	}}, Options{})
+	assert.EqualError(t, err, `command "create-cluster" of scriptCluster can not run in the background`)
+}
@@ -4,13 +4,33 @@ package expressions
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"sync"
 
 	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
 )
 
-func buildProgram(expr string, env *cel.Env) (cel.Program, error) {
+// VarsVariable is the name of the CEL variable holding the template and test case variables.
+// It is not declared if a resource reference uses the same name.
+const VarsVariable = "vars"
+
+// Program is a compiled CEL expression.
+type Program struct {
+	cel.Program
+
+	env *cel.Env
+	ast *cel.Ast
+
+	// The exhaustive program records the values of all sub-expressions for failure diagnostics.
+	// It is only built once an evaluation fails.
+	exhaustiveOnce sync.Once
+	exhaustive     cel.Program
+}
+
+func buildProgram(expr string, env *cel.Env) (*Program, error) {
 	ast, issues := env.Compile(expr)
 	if issues != nil && issues.Err() != nil {
 		return nil, fmt.Errorf("type-check error: %s", issues.Err())
@@ -21,7 +41,29 @@ func buildProgram(expr string, env *cel.Env) (cel.Program, error) {
 		return nil, fmt.Errorf("program construction error: %w", err)
 	}
 
-	return prg, nil
+	return &Program{Program: prg, env: env, ast: ast}, nil
+}
+
+// evalDetails evaluates the program exhaustively with variables, and returns the details of the evaluation,
+// i.e. the values of all sub-expressions, or nil if the exhaustive program can not be built.
+func (p *Program) evalDetails(variables map[string]interface{}) *cel.EvalDetails {
+	p.exhaustiveOnce.Do(func() {
+		if p.env == nil || p.ast == nil {
+			return
+		}
+		if prg, err := p.env.Program(p.ast, cel.EvalOptions(cel.OptExhaustiveEval)); err == nil {
+			p.exhaustive = prg
+		}
+	})
+	if p.exhaustive == nil {
+		return nil
+	}
+	// The evaluation error, if any, is that of the program, and the state holds the values evaluated before it.
+	_, details, err := p.exhaustive.Eval(variables)
+	if err != nil && details == nil {
+		return nil
+	}
+	return details
 }
 
 func buildEnv(resourceRefs []harness.TestResourceRef) (*cel.Env, error) {
@@ -36,16 +78,25 @@ func buildEnv(resourceRefs []harness.TestResourceRef) (*cel.Env, error) {
 		return nil, fmt.Errorf("failed to load resource reference(s): %w", errors.Join(errs...))
 	}
 
-	env, err := cel.NewEnv()
+	env, err := cel.NewEnv(envOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create environment: %w", err)
 	}
 
+	varsShadowed := false
 	for _, resourceRef := range resourceRefs {
 		env, err = env.Extend(cel.Variable(resourceRef.Ref, cel.DynType))
 		if err != nil {
 			return nil, fmt.Errorf("failed to add resource parameter '%v' to environment: %w", resourceRef.Ref, err)
 		}
+		varsShadowed = varsShadowed || resourceRef.Ref == VarsVariable
+	}
+
+	if !varsShadowed {
+		env, err = env.Extend(cel.Variable(VarsVariable, cel.MapType(cel.StringType, cel.DynType)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s parameter to environment: %w", VarsVariable, err)
+		}
 	}
 
 	return env, nil
@@ -53,7 +104,7 @@ func buildEnv(resourceRefs []harness.TestResourceRef) (*cel.Env, error) {
 
 // RunAssertExpressions evaluates a set of CEL expressions.
 func RunAssertExpressions(
-	programs map[string]cel.Program,
+	programs map[string]*Program,
 	variables map[string]interface{},
 	assertAny,
 	assertAll []*harness.Assertion,
@@ -77,18 +128,26 @@ func RunAssertExpressions(
 	}
 
 	if len(assertAny) != 0 && len(anyExprErrors) == len(assertAny) {
-		errs = append(errs, fmt.Errorf("no expression evaluated to true: %w", errors.Join(anyExprErrors...)))
+		err := fmt.Errorf("no expression evaluated to true: %w", errors.Join(anyExprErrors...))
+		if best := closestAlternative(anyExprErrors); best != nil {
+			err = fmt.Errorf("%w\nclosest alternative was %q with %d of %d conditions true", err, best.Expression, best.Satisfied, best.Conditions)
+		}
+		errs = append(errs, err)
 	}
 
 	if len(allExprErrors) > 0 {
 		errs = append(errs, fmt.Errorf("not all assertAll expressions evaluated to true: %w", errors.Join(allExprErrors...)))
 	}
 
+	if len(errs) > 0 {
+		errs = append(errs, fmt.Errorf("expressions were evaluated with:\n%s", snapshot(variables)))
+	}
+
 	return errs
 }
 
 // LoadPrograms loads and compiles CEL programs from test assertions.
-func LoadPrograms(testAssert *harness.TestAssert) (map[string]cel.Program, error) {
+func LoadPrograms(testAssert *harness.TestAssert) (map[string]*Program, error) {
 	var errs []error
 	var assertions []*harness.Assertion
 	assertions = append(assertions, testAssert.AssertAny...)
@@ -102,7 +161,7 @@ func LoadPrograms(testAssert *harness.TestAssert) (map[string]cel.Program, error
 	if len(assertions) == 0 {
 		return nil, nil
 	}
-	programs := make(map[string]cel.Program)
+	programs := make(map[string]*Program)
 
 	for _, assertion := range assertions {
 		if prg, err := buildProgram(assertion.CELExpression, env); err != nil {
@@ -123,7 +182,7 @@ func LoadPrograms(testAssert *harness.TestAssert) (map[string]cel.Program, error
 }
 
 func evaluateExpression(expr string,
-	programs map[string]cel.Program,
+	programs map[string]*Program,
 	variables map[string]interface{},
 ) error {
 	prg, ok := programs[expr]
@@ -131,13 +190,59 @@ func evaluateExpression(expr string,
 		return fmt.Errorf("couldn't find pre-built parsed CEL expression %q", expr)
 	}
 	out, _, err := prg.Eval(variables)
-	if err != nil {
-		return fmt.Errorf("failed to evaluate CEL expression: %w", err)
+	if err != nil || out.Value() != true {
+		return newExpressionError(expr, prg, variables, out, err)
+	}
+
+	return nil
+}
+
+// LoadBindingPrograms compiles the CEL expressions of bindings, keyed by binding name.
+// Bindings using JSONPath are skipped.
+func LoadBindingPrograms(bindings []harness.Binding) (map[string]*Program, error) {
+	var errs []error
+	programs := make(map[string]*Program)
+
+	for _, binding := range bindings {
+		if binding.CELExpression == "" {
+			continue
+		}
+		env, err := buildEnv([]harness.TestResourceRef{binding.ResourceRef})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("binding %q: failed to build CEL environment: %w", binding.Name, err))
+			continue
+		}
+		prg, err := buildProgram(binding.CELExpression, env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("binding %q: failed to build CEL program from expression %q: %w", binding.Name, binding.CELExpression, err))
+			continue
+		}
+		programs[binding.Name] = prg
 	}
 
-	if out.Value() != true {
-		return fmt.Errorf("expression %q evaluated to '%v'", expr, out.Value())
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load binding expression(s): %w", errors.Join(errs...))
+	}
+	if len(programs) == 0 {
+		return nil, nil
 	}
+	return programs, nil
+}
 
-	return nil
+// EvaluateBinding evaluates a pre-built binding program and returns its result as a JSON-compatible value.
+func EvaluateBinding(prg *Program, variables map[string]interface{}) (any, error) {
+	out, _, err := prg.Eval(variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate CEL expression: %w", err)
+	}
+
+	native, err := out.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert CEL value of type %s: %w", out.Type(), err)
+	}
+	value, ok := native.(*structpb.Value)
+	if !ok {
+		return nil, fmt.Errorf("unexpected conversion result of type %T", native)
+	}
+	return value.AsInterface(), nil
 }
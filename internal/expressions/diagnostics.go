@@ -0,0 +1,408 @@
+package expressions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+	"github.com/google/cel-go/parser"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+)
+
+// maxValueLength is the length after which values of sub-expressions are truncated in diagnostics.
+const maxValueLength = 120
+
+// ExpressionError is returned when an expression does not evaluate to true.
+type ExpressionError struct {
+	Expression string
+	// Result is the value the expression evaluated to, if its evaluation did not fail.
+	Result ref.Val
+	// Err is the evaluation error, if any.
+	Err error
+	// Values holds the values of the sub-expressions, in the order of their appearance in the expression.
+	Values []SubExpression
+	// Conditions is the number of top-level conditions of the expression, i.e. the operands of its outermost `&&`
+	// operators, and Satisfied the number of those which evaluated to true.
+	Conditions, Satisfied int
+}
+
+// SubExpression is the value of a part of an expression.
+type SubExpression struct {
+	Expression string
+	Value      string
+}
+
+// Error implements the error interface.
+func (e *ExpressionError) Error() string {
+	var sb strings.Builder
+	if e.Err != nil {
+		fmt.Fprintf(&sb, "failed to evaluate CEL expression %q: %v", e.Expression, e.Err)
+	} else {
+		fmt.Fprintf(&sb, "expression %q evaluated to '%v'", e.Expression, e.Result.Value())
+	}
+	for _, v := range e.Values {
+		fmt.Fprintf(&sb, "\n    %s = %s", v.Expression, v.Value)
+	}
+	return sb.String()
+}
+
+// Unwrap returns the evaluation error, if any.
+func (e *ExpressionError) Unwrap() error {
+	return e.Err
+}
+
+// newExpressionError builds an ExpressionError from the result of the evaluation of prg with variables,
+// re-evaluating it exhaustively for the values of its sub-expressions.
+func newExpressionError(expr string, prg *Program, variables map[string]any, out ref.Val, err error) *ExpressionError {
+	exprErr := &ExpressionError{Expression: expr, Result: out, Err: err}
+	details := prg.evalDetails(variables)
+	if details == nil {
+		return exprErr
+	}
+	state := details.State()
+	native := prg.ast.NativeRep()
+
+	seen := map[string]bool{}
+	visit(native.Expr(), true, func(e ast.Expr) {
+		val, ok := state.Value(e.ID())
+		if !ok {
+			return
+		}
+		sensitivity := sensitivityOf(e, state, variables)
+		if sensitivity == omittedValue {
+			return
+		}
+		text, err := parser.Unparse(e, native.SourceInfo())
+		if err != nil || seen[text] {
+			return
+		}
+		seen[text] = true
+		value := kubernetes.RedactedValue
+		if sensitivity == notSensitive || val.Type() == types.BoolType {
+			value = formatValue(val)
+		}
+		exprErr.Values = append(exprErr.Values, SubExpression{Expression: text, Value: value})
+	})
+
+	for _, c := range conditions(native.Expr()) {
+		exprErr.Conditions++
+		if val, ok := state.Value(c.ID()); ok && val == types.True {
+			exprErr.Satisfied++
+		}
+	}
+	return exprErr
+}
+
+// visit calls f for the sub-expressions of e which are worth reporting, in pre-order.
+// Literals and logical operators are skipped, and only the range of comprehensions is descended into
+// since the state only holds the values of their last iteration.
+func visit(e ast.Expr, root bool, f func(ast.Expr)) {
+	switch e.Kind() { //nolint:exhaustive
+	case ast.IdentKind:
+		if !root {
+			f(e)
+		}
+	case ast.SelectKind:
+		if !root {
+			f(e)
+		}
+		// Only the outermost field selection of a chain is reported.
+		if operand := e.AsSelect().Operand(); operand.Kind() != ast.SelectKind && operand.Kind() != ast.IdentKind {
+			visit(operand, false, f)
+		}
+	case ast.CallKind:
+		call := e.AsCall()
+		switch call.FunctionName() {
+		case operators.LogicalAnd, operators.LogicalOr, operators.LogicalNot, operators.Conditional:
+		default:
+			if !root {
+				f(e)
+			}
+		}
+		if call.IsMemberFunction() {
+			visit(call.Target(), false, f)
+		}
+		for _, arg := range call.Args() {
+			visit(arg, false, f)
+		}
+	case ast.ListKind:
+		for _, elem := range e.AsList().Elements() {
+			visit(elem, false, f)
+		}
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			visit(entry.AsMapEntry().Value(), false, f)
+		}
+	case ast.ComprehensionKind:
+		if !root {
+			f(e)
+		}
+		visit(e.AsComprehension().IterRange(), false, f)
+	}
+}
+
+// sensitivity tells whether the value of a sub-expression may be reported.
+type sensitivity int
+
+const (
+	notSensitive sensitivity = iota
+	// redactedValue sub-expressions are reported with their value redacted, unless it is a boolean.
+	redactedValue
+	// omittedValue sub-expressions are not reported at all.
+	omittedValue
+)
+
+// secretFields are the fields of Secrets which hold sensitive data.
+var secretFields = []string{"data", "stringData", "annotations"}
+
+// sensitivityOf returns whether the value of e may be reported. Sub-expressions rooted at vars are omitted since
+// vars may hold the output of commands which is not logged. Those which read the data of a Secret, or any part of
+// it which is not a scalar, are redacted, and so are those computed from any of these.
+func sensitivityOf(e ast.Expr, state interpreter.EvalState, variables map[string]any) sensitivity {
+	if root, fields, ok := selection(e); ok {
+		switch {
+		case root == VarsVariable:
+			return omittedValue
+		case root == SelfVariable && isSecret(variables[ObjectVariable]):
+			// The path of self within the object is not known here.
+			return redactedValue
+		case !holdsSecret(variables[root]):
+			return notSensitive
+		}
+		if val, ok := state.Value(e.ID()); ok && isScalar(val) &&
+			!slices.ContainsFunc(fields, func(field string) bool { return slices.Contains(secretFields, field) }) {
+			return notSensitive
+		}
+		return redactedValue
+	}
+	for _, operand := range operands(e) {
+		if sensitivityOf(operand, state, variables) != notSensitive {
+			return redactedValue
+		}
+	}
+	return notSensitive
+}
+
+// selection returns the variable a chain of field selections and indexations starts from, along with the fields
+// it selects, or false if e is not such a chain.
+func selection(e ast.Expr) (string, []string, bool) {
+	var fields []string
+	for {
+		switch e.Kind() { //nolint:exhaustive
+		case ast.IdentKind:
+			return e.AsIdent(), fields, true
+		case ast.SelectKind:
+			fields = append(fields, e.AsSelect().FieldName())
+			e = e.AsSelect().Operand()
+		case ast.CallKind:
+			call := e.AsCall()
+			switch call.FunctionName() {
+			case operators.Index, operators.OptIndex, operators.OptSelect:
+			default:
+				return "", nil, false
+			}
+			if key := call.Args()[1]; key.Kind() == ast.LiteralKind {
+				if field, ok := key.AsLiteral().(types.String); ok {
+					fields = append(fields, string(field))
+				}
+			}
+			e = call.Args()[0]
+		default:
+			return "", nil, false
+		}
+	}
+}
+
+// operands returns the sub-expressions the value of e is computed from.
+func operands(e ast.Expr) []ast.Expr {
+	switch e.Kind() { //nolint:exhaustive
+	case ast.SelectKind:
+		return []ast.Expr{e.AsSelect().Operand()}
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			return append([]ast.Expr{call.Target()}, call.Args()...)
+		}
+		return call.Args()
+	case ast.ListKind:
+		return e.AsList().Elements()
+	case ast.MapKind:
+		var values []ast.Expr
+		for _, entry := range e.AsMap().Entries() {
+			values = append(values, entry.AsMapEntry().Key(), entry.AsMapEntry().Value())
+		}
+		return values
+	case ast.ComprehensionKind:
+		return []ast.Expr{e.AsComprehension().IterRange()}
+	default:
+		return nil
+	}
+}
+
+func isScalar(val ref.Val) bool {
+	switch val.(type) {
+	case types.Bool, types.Int, types.Uint, types.Double, types.String, types.Null:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSecret returns whether value is a Secret.
+func isSecret(value any) bool {
+	obj, ok := value.(map[string]any)
+	return ok && obj["kind"] == "Secret"
+}
+
+// holdsSecret returns whether value is a Secret or a list of objects with a Secret among them.
+func holdsSecret(value any) bool {
+	if items, ok := value.([]any); ok {
+		return slices.ContainsFunc(items, isSecret)
+	}
+	return isSecret(value)
+}
+
+// conditions returns the operands of the outermost `&&` operators of e.
+func conditions(e ast.Expr) []ast.Expr {
+	if e.Kind() == ast.CallKind && e.AsCall().FunctionName() == operators.LogicalAnd {
+		var operands []ast.Expr
+		for _, arg := range e.AsCall().Args() {
+			operands = append(operands, conditions(arg)...)
+		}
+		return operands
+	}
+	return []ast.Expr{e}
+}
+
+func formatValue(val ref.Val) string {
+	var formatted string
+	if types.IsError(val) {
+		formatted = fmt.Sprintf("<error: %v>", val)
+	} else if native, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{})); err == nil {
+		if value, ok := native.(*structpb.Value); ok {
+			if b, err := json.Marshal(value.AsInterface()); err == nil {
+				formatted = string(b)
+			}
+		}
+	}
+	if formatted == "" {
+		formatted = fmt.Sprintf("%v", val.Value())
+	}
+	if len(formatted) > maxValueLength {
+		formatted = formatted[:maxValueLength] + "..."
+	}
+	return formatted
+}
+
+// closestAlternative returns the failed expression with the highest proportion of satisfied conditions,
+// or nil if none of them has any satisfied condition.
+func closestAlternative(errs []error) *ExpressionError {
+	var best *ExpressionError
+	for _, err := range errs {
+		var e *ExpressionError
+		if !errors.As(err, &e) || e.Satisfied == 0 {
+			continue
+		}
+		if best == nil || e.Satisfied*best.Conditions > best.Satisfied*e.Conditions {
+			best = e
+		}
+	}
+	return best
+}
+
+// snapshot renders the referenced resources and lists used in the evaluation of expressions as YAML, without managed fields
+// and with the data of Secrets redacted. Other variables, i.e. vars, are left out since they may hold the output
+// of commands which is not logged.
+func snapshot(variables map[string]any) string {
+	names := make([]string, 0, len(variables))
+	for name, value := range variables {
+		switch value := value.(type) {
+		case nil, []any:
+			// Absent optional resources are nil, and lists are referenced as such.
+		case map[string]any:
+			if value["kind"] == nil {
+				continue
+			}
+		default:
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		b, err := yaml.Marshal(withoutManagedFields(redactSecret(variables[name])))
+		if err != nil {
+			fmt.Fprintf(&sb, "--- %s: %v\n", name, err)
+			continue
+		}
+		fmt.Fprintf(&sb, "--- %s:\n%s", name, b)
+	}
+	return sb.String()
+}
+
+// redactSecret returns a copy of value with its data redacted if it is a Secret or a list holding Secrets,
+// or value otherwise.
+func redactSecret(value any) any {
+	if items, ok := value.([]any); ok {
+		if !slices.ContainsFunc(items, isSecret) {
+			return value
+		}
+		redacted := make([]any, 0, len(items))
+		for _, item := range items {
+			redacted = append(redacted, redactSecret(item))
+		}
+		return redacted
+	}
+	obj, ok := value.(map[string]any)
+	if !ok || !isSecret(obj) {
+		return value
+	}
+	secret := (&unstructured.Unstructured{Object: obj}).DeepCopy()
+	kubernetes.RedactSecret(secret)
+	return secret.Object
+}
+
+func withoutManagedFields(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		metadata, ok := value["metadata"].(map[string]any)
+		if !ok || metadata["managedFields"] == nil {
+			return value
+		}
+		copied := make(map[string]any, len(value))
+		for k, v := range value {
+			copied[k] = v
+		}
+		copiedMetadata := make(map[string]any, len(metadata))
+		for k, v := range metadata {
+			if k != "managedFields" {
+				copiedMetadata[k] = v
+			}
+		}
+		copied["metadata"] = copiedMetadata
+		return copied
+	case []any:
+		items := make([]any, 0, len(value))
+		for _, item := range value {
+			items = append(items, withoutManagedFields(item))
+		}
+		return items
+	default:
+		return value
+	}
+}
@@ -0,0 +1,195 @@
+package expressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+func TestRunAssertExpressionsDiagnostics(t *testing.T) {
+	testAssert := &harness.TestAssert{
+		ResourceRefs: []harness.TestResourceRef{
+			{APIVersion: "v1", Kind: "Pod", Name: "web", Ref: "pod"},
+		},
+		AssertAny: []*harness.Assertion{
+			{CELExpression: "pod.status.phase == 'Succeeded'"},
+			{CELExpression: "pod.status.phase == 'Running' && pod.metadata.name == 'web' && size(pod.spec.containers) > 1"},
+		},
+		AssertAll: []*harness.Assertion{
+			{CELExpression: "pod.spec.containers.all(c, c.image.startsWith('nginx:'))"},
+		},
+	}
+	programs, err := LoadPrograms(testAssert)
+	require.NoError(t, err)
+
+	variables := map[string]any{
+		"pod": map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]any{
+				"name":          "web",
+				"managedFields": []any{map[string]any{"manager": "kubectl"}},
+			},
+			"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "web", "image": "httpd:2"}},
+			},
+			"status": map[string]any{"phase": "Running"},
+		},
+	}
+
+	errs := RunAssertExpressions(programs, variables, testAssert.AssertAny, testAssert.AssertAll)
+	require.Len(t, errs, 3)
+
+	assert.Contains(t, errs[0].Error(), `expression "pod.status.phase == 'Succeeded'" evaluated to 'false'
+    pod.status.phase = "Running"`)
+	assert.Contains(t, errs[0].Error(), `size(pod.spec.containers) = 1`)
+	assert.Contains(t, errs[0].Error(),
+		`closest alternative was "pod.status.phase == 'Running' && pod.metadata.name == 'web' && size(pod.spec.containers) > 1" with 2 of 3 conditions true`)
+
+	assert.Contains(t, errs[1].Error(), `expression "pod.spec.containers.all(c, c.image.startsWith('nginx:'))" evaluated to 'false'
+    pod.spec.containers = [{"image":"httpd:2","name":"web"}]`)
+
+	assert.Contains(t, errs[2].Error(), "expressions were evaluated with:\n--- pod:\n")
+	assert.Contains(t, errs[2].Error(), "phase: Running")
+	assert.NotContains(t, errs[2].Error(), "managedFields")
+}
+
+func TestRunAssertExpressionsSnapshotRedaction(t *testing.T) {
+	testAssert := &harness.TestAssert{
+		ResourceRefs: []harness.TestResourceRef{
+			{APIVersion: "v1", Kind: "Secret", Name: "credentials", Ref: "secret"},
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "missing", Ref: "config", Optional: true},
+			{APIVersion: "v1", Kind: "Secret", Ref: "secrets", List: true},
+		},
+		AssertAll: []*harness.Assertion{
+			{CELExpression: "secret.data.password == vars.password && size(secrets) == 2"},
+		},
+	}
+	programs, err := LoadPrograms(testAssert)
+	require.NoError(t, err)
+
+	data := map[string]any{"password": "aHVudGVyMg=="}
+	variables := map[string]any{
+		"secret": map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]any{"name": "credentials"},
+			"data":       data,
+		},
+		"config": nil,
+		"secrets": []any{map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"name":          "token",
+				"managedFields": []any{map[string]any{"manager": "kubectl"}},
+			},
+			"stringData": map[string]any{"token": "dG9rZW4="},
+		}},
+		VarsVariable: map[string]any{"password": "c2VjcmV0"},
+	}
+
+	errs := RunAssertExpressions(programs, variables, nil, testAssert.AssertAll)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[1].Error(), "--- config:\nnull\n")
+	assert.Contains(t, errs[1].Error(), "--- secret:\n")
+	assert.Contains(t, errs[1].Error(), "password: REDACTED")
+	assert.NotContains(t, errs[1].Error(), "aHVudGVyMg==")
+	assert.Contains(t, errs[1].Error(), "--- secrets:\n")
+	assert.Contains(t, errs[1].Error(), "token: REDACTED")
+	assert.NotContains(t, errs[1].Error(), "dG9rZW4=")
+	assert.NotContains(t, errs[1].Error(), "managedFields")
+	assert.NotContains(t, errs[1].Error(), "--- vars:")
+	assert.NotContains(t, errs[1].Error(), "c2VjcmV0")
+	assert.Equal(t, "aHVudGVyMg==", data["password"], "the variables are not modified")
+}
+
+func TestRunAssertExpressionsEvaluationError(t *testing.T) {
+	testAssert := &harness.TestAssert{
+		ResourceRefs: []harness.TestResourceRef{
+			{APIVersion: "v1", Kind: "Pod", Name: "web", Ref: "pod"},
+		},
+		AssertAll: []*harness.Assertion{
+			{CELExpression: "pod.status.phase == 'Running'"},
+		},
+	}
+	programs, err := LoadPrograms(testAssert)
+	require.NoError(t, err)
+
+	errs := RunAssertExpressions(programs, map[string]any{"pod": map[string]any{}}, nil, testAssert.AssertAll)
+	require.Len(t, errs, 2)
+
+	var exprErr *ExpressionError
+	require.ErrorAs(t, errs[0], &exprErr)
+	assert.Error(t, exprErr.Err)
+	assert.Contains(t, errs[0].Error(), `failed to evaluate CEL expression "pod.status.phase == 'Running'": no such key: status`)
+}
+
+func TestRunAssertExpressionsExhaustiveOnFailure(t *testing.T) {
+	testAssert := &harness.TestAssert{
+		ResourceRefs: []harness.TestResourceRef{
+			{APIVersion: "v1", Kind: "Pod", Name: "web", Ref: "pod"},
+		},
+		AssertAll: []*harness.Assertion{
+			{CELExpression: "pod.status.phase == 'Running'"},
+		},
+	}
+	programs, err := LoadPrograms(testAssert)
+	require.NoError(t, err)
+	prg := programs["pod.status.phase == 'Running'"]
+
+	pod := func(phase string) map[string]any {
+		return map[string]any{"pod": map[string]any{"kind": "Pod", "status": map[string]any{"phase": phase}}}
+	}
+
+	errs := RunAssertExpressions(programs, pod("Running"), nil, testAssert.AssertAll)
+	assert.Empty(t, errs)
+	assert.Nil(t, prg.exhaustive, "passing expressions are not evaluated exhaustively")
+
+	errs = RunAssertExpressions(programs, pod("Pending"), nil, testAssert.AssertAll)
+	require.NotEmpty(t, errs)
+	assert.NotNil(t, prg.exhaustive)
+	assert.Contains(t, errs[0].Error(), `pod.status.phase = "Pending"`)
+}
+
+func TestRunAssertExpressionsValuesRedaction(t *testing.T) {
+	testAssert := &harness.TestAssert{
+		ResourceRefs: []harness.TestResourceRef{
+			{APIVersion: "v1", Kind: "Secret", Name: "credentials", Ref: "secret"},
+			{APIVersion: "v1", Kind: "Secret", Ref: "secrets", List: true, LabelSelector: "app=web"},
+		},
+		AssertAll: []*harness.Assertion{
+			{CELExpression: "secret.data.password == 'x' && secret.metadata.name == 'other'"},
+			{CELExpression: "secrets[0].data['password'] + vars.token == 'x'"},
+			{CELExpression: "vars.token == 'x' && size(secret) == 1"},
+		},
+	}
+	programs, err := LoadPrograms(testAssert)
+	require.NoError(t, err)
+
+	secret := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "credentials"},
+		"data":       map[string]any{"password": "aHVudGVyMg=="},
+	}
+	variables := map[string]any{
+		"secret":     secret,
+		"secrets":    []any{secret},
+		VarsVariable: map[string]any{"token": "c2VjcmV0"},
+	}
+
+	errs := RunAssertExpressions(programs, variables, nil, testAssert.AssertAll)
+	require.Len(t, errs, 2)
+	assert.NotContains(t, errs[0].Error(), "aHVudGVyMg==")
+	assert.NotContains(t, errs[0].Error(), "c2VjcmV0")
+	assert.NotContains(t, errs[0].Error(), "\n    vars.token = ")
+	assert.Contains(t, errs[0].Error(), "secret.data.password = REDACTED")
+	assert.Contains(t, errs[0].Error(), `secret.metadata.name = "credentials"`)
+	assert.Contains(t, errs[0].Error(), `secrets[0].data["password"] + vars.token = REDACTED`)
+	assert.Contains(t, errs[0].Error(), `vars.token == "x" = false`)
+	assert.Contains(t, errs[0].Error(), "size(secret) = REDACTED")
+}
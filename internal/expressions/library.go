@@ -0,0 +1,160 @@
+package expressions
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/cel/library"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// envOptions returns the libraries available to all expressions: the CEL extensions and Kubernetes libraries
+// available in ValidatingAdmissionPolicy rules, and the kuttl helpers.
+func envOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.CrossTypeNumericComparisons(true),
+		cel.OptionalTypes(),
+		cel.DefaultUTCTimeZone(true),
+		ext.Strings(),
+		ext.Sets(),
+		ext.TwoVarComprehensions(),
+		library.URLs(),
+		library.Regex(),
+		library.Lists(),
+		library.Quantity(),
+		library.IP(),
+		library.CIDR(),
+		library.Format(),
+		library.SemverLib(),
+		cel.Lib(kuttlLib{}),
+	}
+}
+
+// kuttlLib provides helper functions for expressions on Kubernetes objects.
+//
+//	hasCondition(obj, type, status) returns true if obj has a status condition of the given type and status.
+//	age(obj) returns the duration since the creation of obj.
+//	ownerOf(owner, obj) returns true if owner is listed in the owner references of obj.
+type kuttlLib struct{}
+
+func (kuttlLib) LibraryName() string {
+	return "kuttl.dev.lib"
+}
+
+func (kuttlLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("hasCondition",
+			cel.Overload("has_condition_dyn_string_string",
+				[]*cel.Type{cel.DynType, cel.StringType, cel.StringType}, cel.BoolType,
+				cel.FunctionBinding(hasCondition))),
+		cel.Function("age",
+			cel.Overload("age_dyn",
+				[]*cel.Type{cel.DynType}, cel.DurationType,
+				cel.UnaryBinding(age))),
+		cel.Function("ownerOf",
+			cel.Overload("owner_of_dyn_dyn",
+				[]*cel.Type{cel.DynType, cel.DynType}, cel.BoolType,
+				cel.BinaryBinding(ownerOf))),
+	}
+}
+
+func (kuttlLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func hasCondition(args ...ref.Val) ref.Val {
+	obj, err := toObject(args[0])
+	if err != nil {
+		return types.NewErr("hasCondition: %v", err)
+	}
+	conditionType, ok := args[1].Value().(string)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(args[1])
+	}
+	status, ok := args[2].Value().(string)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(args[2])
+	}
+
+	conditions, err := kubernetes.NestedMaps(obj, "status", "conditions")
+	if err != nil {
+		return types.NewErr("hasCondition: %v", err)
+	}
+	for _, condition := range conditions {
+		if condition["type"] == conditionType && condition["status"] == status {
+			return types.True
+		}
+	}
+	return types.False
+}
+
+func age(arg ref.Val) ref.Val {
+	obj, err := toObject(arg)
+	if err != nil {
+		return types.NewErr("age: %v", err)
+	}
+	created, found, err := unstructured.NestedString(obj, "metadata", "creationTimestamp")
+	if err != nil {
+		return types.NewErr("age: %v", err)
+	}
+	if !found {
+		return types.NewErr("age: object has no metadata.creationTimestamp")
+	}
+	timestamp, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		return types.NewErr("age: invalid creationTimestamp %q: %v", created, err)
+	}
+	return types.Duration{Duration: now().Sub(timestamp)}
+}
+
+func ownerOf(ownerArg, objArg ref.Val) ref.Val {
+	owner, err := toObject(ownerArg)
+	if err != nil {
+		return types.NewErr("ownerOf: %v", err)
+	}
+	obj, err := toObject(objArg)
+	if err != nil {
+		return types.NewErr("ownerOf: %v", err)
+	}
+	uid, _, err := unstructured.NestedString(owner, "metadata", "uid")
+	if err != nil {
+		return types.NewErr("ownerOf: owner %v", err)
+	}
+	if uid == "" {
+		return types.NewErr("ownerOf: owner has no metadata.uid")
+	}
+
+	ownerReferences, err := kubernetes.NestedMaps(obj, "metadata", "ownerReferences")
+	if err != nil {
+		return types.NewErr("ownerOf: %v", err)
+	}
+	for _, ownerReference := range ownerReferences {
+		if ownerReference["uid"] == uid {
+			return types.True
+		}
+	}
+	return types.False
+}
+
+// toObject converts a CEL value to the map representation of a Kubernetes object.
+func toObject(val ref.Val) (map[string]any, error) {
+	native, err := val.ConvertToNative(reflect.TypeOf(map[string]any{}))
+	if err != nil {
+		return nil, fmt.Errorf("expected an object, got %s", val.Type())
+	}
+	obj, ok := native.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %T", native)
+	}
+	return obj, nil
+}
@@ -0,0 +1,95 @@
+package expressions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+func TestLibrary(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer func(orig func() time.Time) { now = orig }(now)
+	now = func() time.Time { return created.Add(90 * time.Second) }
+
+	deployment := map[string]any{
+		"metadata": map[string]any{
+			"name":              "web",
+			"uid":               "1234",
+			"creationTimestamp": created.Format(time.RFC3339),
+		},
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+				map[string]any{"type": "Progressing", "status": "False"},
+			},
+		},
+	}
+	replicaSet := map[string]any{
+		"metadata": map[string]any{
+			"name":            "web-abc",
+			"ownerReferences": []any{map[string]any{"kind": "Deployment", "uid": "1234"}},
+		},
+	}
+	malformed := map[string]any{
+		"metadata": map[string]any{
+			"name":              "bad",
+			"uid":               42,
+			"creationTimestamp": 42,
+			"ownerReferences":   "web",
+		},
+		"status": map[string]any{"conditions": "Available"},
+	}
+	variables := map[string]any{"deployment": deployment, "rs": replicaSet, "bad": malformed, VarsVariable: map[string]any{}}
+
+	env, err := buildEnv([]harness.TestResourceRef{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Ref: "deployment"},
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", Ref: "rs"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "bad", Ref: "bad"},
+	})
+	require.NoError(t, err)
+
+	for name, tt := range map[string]struct {
+		expr     string
+		expected any
+		errMsg   string
+	}{
+		"condition present":     {expr: "hasCondition(deployment, 'Available', 'True')", expected: true},
+		"condition with status": {expr: "hasCondition(deployment, 'Progressing', 'True')", expected: false},
+		"condition missing":     {expr: "hasCondition(rs, 'Available', 'True')", expected: false},
+		"age":                   {expr: "age(deployment) > duration('1m') && age(deployment) < duration('2m')", expected: true},
+		"age without timestamp": {expr: "age(rs) > duration('1m')", errMsg: "no metadata.creationTimestamp"},
+		"owner":                 {expr: "ownerOf(deployment, rs)", expected: true},
+		"not owner":             {expr: "ownerOf(deployment, deployment)", expected: false},
+		"owner without uid":     {expr: "ownerOf(rs, deployment)", errMsg: "owner has no metadata.uid"},
+		"malformed conditions":  {expr: "hasCondition(bad, 'Available', 'True')", errMsg: ".status.conditions accessor error"},
+		"malformed timestamp":   {expr: "age(bad) > duration('1m')", errMsg: ".metadata.creationTimestamp accessor error"},
+		"malformed owner uid":   {expr: "ownerOf(bad, rs)", errMsg: ".metadata.uid accessor error"},
+		"malformed owners":      {expr: "ownerOf(deployment, bad)", errMsg: ".metadata.ownerReferences accessor error"},
+		"quantity":              {expr: "quantity('1Gi').isGreaterThan(quantity('512Mi'))", expected: true},
+		"lists":                 {expr: "[1, 2, 3].sum() == 6", expected: true},
+		"regex":                 {expr: "rs.metadata.name.find('[a-z]+$') == 'abc'", expected: true},
+		"strings":               {expr: "deployment.metadata.name.upperAscii() == 'WEB'", expected: true},
+		"semver":                {expr: "semver('1.30.1').isGreaterThan(semver('1.29.0'))", expected: true},
+		"cidr":                  {expr: "cidr('10.0.0.0/8').containsIP(ip('10.1.2.3'))", expected: true},
+		"cross type numeric":    {expr: "1 < 1.5", expected: true},
+		"optional field":        {expr: "deployment.?spec.replicas.orValue(1) == 1", expected: true},
+		"url":                   {expr: "url('https://example.com:8080/path').getPort() == '8080'", expected: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			prg, err := buildProgram(tt.expr, env)
+			require.NoError(t, err)
+
+			out, _, err := prg.Eval(variables)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.Value())
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package expressions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// ObjectExpressionKey is the key holding the CEL expressions attached to an object, or to any map within it,
+// in assert and error files.
+const ObjectExpressionKey = "($cel)"
+
+const (
+	// SelfVariable is the name of the CEL variable holding the part of the actual object at which an
+	// object expression is declared.
+	SelfVariable = "self"
+	// ObjectVariable is the name of the CEL variable holding the whole actual object.
+	ObjectVariable = "object"
+)
+
+// ObjectExpression is a CEL expression attached to an expected object.
+type ObjectExpression struct {
+	// Path to the map holding the expression: map keys are strings and list indices are ints.
+	Path       []any
+	Expression string
+
+	program *Program
+}
+
+// PathString returns the path of the expression in JSONPath-like notation.
+func (e ObjectExpression) PathString() string {
+	var sb strings.Builder
+	for _, p := range e.Path {
+		switch p := p.(type) {
+		case int:
+			fmt.Fprintf(&sb, "[%d]", p)
+		default:
+			fmt.Fprintf(&sb, ".%v", p)
+		}
+	}
+	if sb.Len() == 0 {
+		return "."
+	}
+	return sb.String()
+}
+
+// ExtractObjectExpressions returns a copy of expected without the ObjectExpressionKey entries,
+// along with the compiled expressions they held.
+func ExtractObjectExpressions(expected map[string]any) (map[string]any, []ObjectExpression, error) {
+	var objectExpressions []ObjectExpression
+	var errs []error
+	stripped := extractMap(expected, nil, &objectExpressions, &errs)
+
+	if len(objectExpressions) == 0 && len(errs) == 0 {
+		return stripped, nil, nil
+	}
+
+	env, err := objectEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build CEL environment: %w", err)
+	}
+	for i, e := range objectExpressions {
+		prg, err := buildProgram(e.Expression, env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to build CEL program from expression %q at %s: %w", e.Expression, e.PathString(), err))
+			continue
+		}
+		objectExpressions[i].program = prg
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("failed to load object expression(s): %w", errors.Join(errs...))
+	}
+	return stripped, objectExpressions, nil
+}
+
+func extract(value any, path []any, objectExpressions *[]ObjectExpression, errs *[]error) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return extractMap(value, path, objectExpressions, errs)
+	case []any:
+		out := make([]any, len(value))
+		for i, v := range value {
+			out[i] = extract(v, appendPath(path, i), objectExpressions, errs)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func extractMap(value map[string]any, path []any, objectExpressions *[]ObjectExpression, errs *[]error) map[string]any {
+	out := make(map[string]any, len(value))
+	keys := make([]string, 0, len(value))
+	for k := range value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := value[k]
+		if k != ObjectExpressionKey {
+			out[k] = extract(v, appendPath(path, k), objectExpressions, errs)
+			continue
+		}
+		exprs, err := expressionList(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("invalid %s at %s: %w", ObjectExpressionKey, ObjectExpression{Path: path}.PathString(), err))
+			continue
+		}
+		for _, expr := range exprs {
+			*objectExpressions = append(*objectExpressions, ObjectExpression{Path: path, Expression: expr})
+		}
+	}
+	return out
+}
+
+func appendPath(path []any, elem any) []any {
+	return append(append(make([]any, 0, len(path)+1), path...), elem)
+}
+
+func expressionList(value any) ([]string, error) {
+	switch value := value.(type) {
+	case string:
+		return []string{value}, nil
+	case []any:
+		exprs := make([]string, 0, len(value))
+		for _, v := range value {
+			expr, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, got %T", v)
+			}
+			exprs = append(exprs, expr)
+		}
+		return exprs, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a list of strings, got %T", value)
+	}
+}
+
+// objectEnv returns the CEL environment of object expressions, which is only built once.
+var objectEnv = sync.OnceValues(buildObjectEnv)
+
+func buildObjectEnv() (*cel.Env, error) {
+	env, err := buildEnv(nil)
+	if err != nil {
+		return nil, err
+	}
+	return env.Extend(
+		cel.Variable(SelfVariable, cel.DynType),
+		cel.Variable(ObjectVariable, cel.DynType),
+	)
+}
+
+// Evaluate evaluates the expression against the actual object, which must be a superset of the expected object
+// the expression was extracted from.
+func (e ObjectExpression) Evaluate(actual map[string]any, vars map[string]any) error {
+	var self any = actual
+	for _, p := range e.Path {
+		switch p := p.(type) {
+		case int:
+			list, ok := self.([]any)
+			if !ok || p >= len(list) {
+				return fmt.Errorf("expression %q: %s not found", e.Expression, e.PathString())
+			}
+			self = list[p]
+		case string:
+			m, ok := self.(map[string]any)
+			if !ok {
+				return fmt.Errorf("expression %q: %s not found", e.Expression, e.PathString())
+			}
+			if self, ok = m[p]; !ok {
+				return fmt.Errorf("expression %q: %s not found", e.Expression, e.PathString())
+			}
+		}
+	}
+
+	variables := map[string]any{
+		SelfVariable:   self,
+		ObjectVariable: actual,
+		VarsVariable:   vars,
+	}
+	out, _, err := e.program.Eval(variables)
+	if err != nil || out.Value() != true {
+		return fmt.Errorf("%s: %w", e.PathString(), newExpressionError(e.Expression, e.program, variables, out, err))
+	}
+	return nil
+}
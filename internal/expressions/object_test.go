@@ -0,0 +1,91 @@
+package expressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractObjectExpressions(t *testing.T) {
+	expected := map[string]any{
+		"($cel)": "object.metadata.name == 'web'",
+		"spec": map[string]any{
+			"($cel)": []any{"self.replicas >= 2", "self.replicas <= vars.max"},
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "nginx", "($cel)": "self.image.startsWith('nginx:')"},
+					},
+				},
+			},
+		},
+	}
+
+	stripped, objectExpressions, err := ExtractObjectExpressions(expected)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{map[string]any{"name": "nginx"}},
+				},
+			},
+		},
+	}, stripped)
+	assert.Contains(t, expected, "($cel)", "the expected object must not be modified")
+
+	var paths []string
+	for _, e := range objectExpressions {
+		paths = append(paths, e.PathString())
+	}
+	assert.Equal(t, []string{".", ".spec", ".spec", ".spec.template.spec.containers[0]"}, paths)
+
+	actual := map[string]any{
+		"metadata": map[string]any{"name": "web"},
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{map[string]any{"name": "nginx", "image": "nginx:1.27"}},
+				},
+			},
+		},
+	}
+	for _, e := range objectExpressions {
+		assert.NoError(t, e.Evaluate(actual, map[string]any{"max": int64(5)}))
+	}
+	assert.EqualError(t, objectExpressions[2].Evaluate(actual, map[string]any{"max": int64(2)}),
+		".spec: expression \"self.replicas <= vars.max\" evaluated to 'false'\n"+
+			"    self.replicas = 3")
+	assert.ErrorContains(t, objectExpressions[3].Evaluate(map[string]any{}, nil), "not found")
+}
+
+func TestExtractObjectExpressionsInvalid(t *testing.T) {
+	for name, tt := range map[string]struct {
+		expected map[string]any
+		errMsg   string
+	}{
+		"not a string": {
+			expected: map[string]any{"spec": map[string]any{"($cel)": int64(1)}},
+			errMsg:   "invalid ($cel) at .spec: expected a string or a list of strings, got int64",
+		},
+		"not a list of strings": {
+			expected: map[string]any{"($cel)": []any{"true", int64(1)}},
+			errMsg:   "invalid ($cel) at .: expected a string, got int64",
+		},
+		"invalid expression": {
+			expected: map[string]any{"($cel)": "self."},
+			errMsg:   `failed to build CEL program from expression "self." at .`,
+		},
+		"undeclared variable": {
+			expected: map[string]any{"($cel)": "pod.metadata.name == 'web'"},
+			errMsg:   "undeclared reference to 'pod'",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ExtractObjectExpressions(tt.expected)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
@@ -22,6 +22,9 @@ const (
 	TypeError
 )
 
+// CaseManifestName is the name of the optional file in a test case directory which contains a TestCase object.
+const CaseManifestName = "kuttl-case.yaml"
+
 // Info contains parsed information about a test file name.
 type Info struct {
 	Type Type
@@ -117,3 +120,18 @@ func Parse(fullName string) Info {
 		}
 	}
 }
+
+// ArtifactsDir returns the directory the artifacts of a test case are written to: <root>/<suite>/<case>.
+// The suite, i.e. the test directory as listed in the TestSuite, is turned into a single path component,
+// e.g. "./test/e2e/" into "test_e2e".
+func ArtifactsDir(root, suite, testCase string) string {
+	suite = filepath.ToSlash(filepath.Clean(suite))
+	for strings.HasPrefix(suite, "../") {
+		suite = strings.TrimPrefix(suite, "../")
+	}
+	suite = strings.TrimPrefix(suite, "/")
+	if suite == "." || suite == ".." {
+		suite = ""
+	}
+	return filepath.Join(root, strings.ReplaceAll(suite, "/", "_"), testCase)
+}
@@ -224,3 +224,15 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestArtifactsDir(t *testing.T) {
+	for suite, expected := range map[string]string{
+		"./test/e2e/":      "/artifacts/test_e2e/case",
+		"tests":            "/artifacts/tests/case",
+		"../../shared/e2e": "/artifacts/shared_e2e/case",
+		"/abs/tests":       "/artifacts/abs_tests/case",
+		".":                "/artifacts/case",
+	} {
+		assert.Equal(t, expected, ArtifactsDir("/artifacts", suite, "case"), suite)
+	}
+}
@@ -2,21 +2,18 @@
 package harness
 
 import (
-	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
-	"strings"
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
 	docker "github.com/moby/moby/client"
-	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
@@ -24,15 +21,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
-	kindConfig "sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 
+	"github.com/kudobuilder/kuttl/internal/cluster"
 	"github.com/kudobuilder/kuttl/internal/file"
 	"github.com/kudobuilder/kuttl/internal/http"
-	"github.com/kudobuilder/kuttl/internal/kind"
 	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	"github.com/kudobuilder/kuttl/internal/progress"
 	"github.com/kudobuilder/kuttl/internal/report"
 	"github.com/kudobuilder/kuttl/internal/testcase"
 	testutils "github.com/kudobuilder/kuttl/internal/utils"
@@ -45,6 +41,8 @@ type Harness struct {
 	T            *testing.T
 	RunLabels    labels.Set
 	TemplateVars map[string]any
+	// Progress is the live view of the running test cases, if shown.
+	Progress *progress.Display
 
 	logger        testutils.Logger
 	managerStopCh chan struct{}
@@ -52,8 +50,7 @@ type Harness struct {
 	docker        testutils.DockerClient
 	client        client.Client
 	dclient       discovery.DiscoveryInterface
-	env           *envtest.Environment
-	kind          *kind.Kind
+	provider      cluster.Provider
 	tempPath      string
 	clientLock    sync.Mutex
 	configLock    sync.Mutex
@@ -63,8 +60,13 @@ type Harness struct {
 }
 
 // LoadTests loads all of the tests in a given directory.
-func (h *Harness) LoadTests(dir string) ([]*testcase.Case, error) {
-	dir, err := filepath.Abs(dir)
+func (h *Harness) LoadTests(testDir string) ([]*testcase.Case, error) {
+	dir, err := filepath.Abs(testDir)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactsDir, err := filepath.Abs(h.TestSuite.ArtifactsDir)
 	if err != nil {
 		return nil, err
 	}
@@ -77,24 +79,44 @@ func (h *Harness) LoadTests(dir string) ([]*testcase.Case, error) {
 	var tests []*testcase.Case
 
 	timeout := h.GetTimeout()
-	h.T.Logf("going to run test suite with timeout of %d seconds for each step", timeout)
+	h.GetLogger().Logf("going to run test suite with timeout of %d seconds for each step", timeout)
 
 	for _, dirEntry := range dirEntries {
 		if !dirEntry.IsDir() {
 			continue
 		}
 
-		tests = append(tests, testcase.NewCase(
-			dirEntry.Name(),
-			dir,
-			testcase.WithSkipDelete(h.TestSuite.SkipDelete),
-			testcase.WithNamespace(h.TestSuite.Namespace),
-			testcase.WithTimeout(timeout),
-			testcase.WithLogSuppressions(h.TestSuite.Suppress),
-			testcase.WithIgnoreFiles(h.TestSuite.IgnoreFiles),
-			testcase.WithRunLabels(h.RunLabels),
-			testcase.WithClients(h.Client, h.DiscoveryClient),
-			testcase.WithTemplateVars(h.TemplateVars)))
+		manifest, err := testcase.LoadManifest(filepath.Join(dir, dirEntry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var matrix map[string][]string
+		collectors := h.TestSuite.Collectors
+		if manifest != nil {
+			matrix = manifest.Matrix
+			collectors = slices.Concat(manifest.Collectors, h.TestSuite.Collectors)
+		}
+
+		for _, matrixVars := range testcase.ExpandMatrix(matrix) {
+			tests = append(tests, testcase.NewCase(
+				dirEntry.Name(),
+				dir,
+				testcase.WithSkipDelete(h.TestSuite.SkipDelete),
+				testcase.WithNamespace(h.TestSuite.Namespace),
+				testcase.WithTimeout(timeout),
+				testcase.WithIgnoreFiles(h.TestSuite.IgnoreFiles),
+				testcase.WithFailureBundle(h.TestSuite.FailureBundle),
+				testcase.WithCollectors(collectors),
+				testcase.WithEventRecording(h.TestSuite.EventRecording),
+				testcase.WithRunLabels(h.RunLabels),
+				testcase.WithClients(h.Client, h.DiscoveryClient),
+				testcase.WithConfig(h.Config),
+				testcase.WithTemplateVars(h.TemplateVars),
+				testcase.WithSuiteName(testDir),
+				testcase.WithArtifactsDir(artifactsDir),
+				testcase.WithMatrixVars(matrixVars)))
+		}
 	}
 
 	return tests, nil
@@ -103,12 +125,34 @@ func (h *Harness) LoadTests(dir string) ([]*testcase.Case, error) {
 // GetLogger returns an initialized test logger.
 func (h *Harness) GetLogger() testutils.Logger {
 	if h.logger == nil {
-		h.logger = testutils.NewTestLogger(h.T, "")
+		h.logger = testutils.NewTestLogger(h.T, "", h.logOptions()...)
 	}
 
 	return h.logger
 }
 
+// logOptions returns the options of the loggers of the harness and test cases.
+// The log format and level are validated by the test command, so invalid values fall back to the defaults.
+func (h *Harness) logOptions() []testutils.TestLoggerOption {
+	format, err := testutils.ParseLogFormat(h.TestSuite.LogFormat)
+	if err != nil {
+		format = testutils.LogFormatText
+	}
+	return []testutils.TestLoggerOption{
+		testutils.WithLogFormat(format),
+		testutils.WithLogLevel(h.logLevel()),
+		testutils.WithSuppressions(h.TestSuite.Suppress),
+	}
+}
+
+func (h *Harness) logLevel() testutils.Level {
+	level, err := testutils.ParseLevel(h.TestSuite.LogLevel)
+	if err != nil {
+		return testutils.LevelInfo
+	}
+	return level
+}
+
 // GetTimeout returns the configured timeout for the test suite.
 func (h *Harness) GetTimeout() int {
 	timeout := 30
@@ -120,55 +164,7 @@ func (h *Harness) GetTimeout() int {
 
 // RunKIND starts a KIND cluster.
 func (h *Harness) RunKIND() (*rest.Config, error) {
-	if h.kind == nil {
-		var err error
-
-		err = h.initTempPath()
-		if err != nil {
-			return nil, err
-		}
-
-		kind := kind.NewKind(h.TestSuite.KINDContext, h.kubeconfigPath(), h.GetLogger())
-		h.kind = &kind
-
-		if h.kind.IsRunning() {
-			// we don't take over an existing kind cluster for --start-kind
-			// which means we do not stop that cluster.  User will either need to switch to existing cluster or stop it.
-			h.kind = nil
-			msg := "KIND is already running, unable to start"
-			h.T.Log(msg)
-			return nil, errors.New(msg)
-		}
-
-		kindCfg := &kindConfig.Cluster{}
-
-		if h.TestSuite.KINDConfig != "" {
-			h.T.Logf("Loading KIND config from %s", h.TestSuite.KINDConfig)
-			var err error
-			kindCfg, err = h.loadKindConfig(h.TestSuite.KINDConfig)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		dockerClient, err := h.DockerClient()
-		if err != nil {
-			return nil, err
-		}
-
-		h.addNodeCaches(dockerClient, kindCfg)
-
-		h.T.Log("Starting KIND cluster")
-		if err := h.kind.Run(kindCfg); err != nil {
-			return nil, err
-		}
-
-		if err := h.kind.AddContainers(dockerClient, h.TestSuite.KINDContainers, h.T); err != nil {
-			return nil, err
-		}
-	}
-
-	return clientcmd.BuildConfigFromFlags("", h.kubeconfigPath())
+	return h.startCluster(cluster.Kind)
 }
 
 // initTempPath creates the temp folder if needed.
@@ -176,59 +172,62 @@ func (h *Harness) RunKIND() (*rest.Config, error) {
 func (h *Harness) initTempPath() (err error) {
 	if h.tempPath == "" {
 		h.tempPath, err = os.MkdirTemp("", "kuttl")
-		h.T.Log("temp folder created", h.tempPath)
+		h.GetLogger().Debugf("temp folder created: %s", h.tempPath)
 	}
 	return err
 }
 
-func (h *Harness) addNodeCaches(dockerClient testutils.DockerClient, kindCfg *kindConfig.Cluster) {
-	if !h.TestSuite.KINDNodeCache {
-		return
-	}
-
-	// add a default node if there are none specified.
-	if len(kindCfg.Nodes) == 0 {
-		kindCfg.Nodes = append(kindCfg.Nodes, kindConfig.Node{})
-	}
+// RunTestEnv starts a Kubernetes API server and etcd server for use in the
+// tests and returns the Kubernetes configuration.
+func (h *Harness) RunTestEnv() (*rest.Config, error) {
+	return h.startCluster(cluster.Envtest)
+}
 
-	if h.TestSuite.KINDContext == "" {
-		h.TestSuite.KINDContext = harness.DefaultKINDContext
+// clusterProvider returns the name of the provider of the cluster the tests run against,
+// or an empty string if the tests run against the cluster of the configured kubeconfig.
+func (h *Harness) clusterProvider() string {
+	switch {
+	case h.TestSuite.ClusterProvider != "":
+		return h.TestSuite.ClusterProvider
+	case h.TestSuite.StartControlPlane:
+		return cluster.Envtest
+	case h.TestSuite.StartKIND:
+		return cluster.Kind
+	default:
+		return ""
 	}
+}
 
-	for index := range kindCfg.Nodes {
-		result, err := dockerClient.VolumeCreate(context.TODO(), docker.VolumeCreateOptions{
-			Driver: "local",
-			Name:   fmt.Sprintf("%s-%d", h.TestSuite.KINDContext, index),
+// startCluster starts a cluster with the named provider, loads the containers of the test suite into it,
+// and returns its Kubernetes configuration.
+func (h *Harness) startCluster(name string) (*rest.Config, error) {
+	if h.provider == nil {
+		if err := h.initTempPath(); err != nil {
+			return nil, err
+		}
+		provider, err := cluster.New(name, &h.TestSuite, cluster.Options{
+			Logger:  h.GetLogger(),
+			TempDir: h.tempPath,
+			Docker:  h.DockerClient,
 		})
 		if err != nil {
-			h.T.Log("error creating volume for node", err)
-			continue
+			return nil, err
 		}
+		// The provider is stopped by Stop even if it fails to start, as the cluster may be partially created.
+		h.provider = provider
 
-		h.T.Log("node mount point", result.Volume.Mountpoint)
-		kindCfg.Nodes[index].ExtraMounts = append(kindCfg.Nodes[index].ExtraMounts, kindConfig.Mount{
-			ContainerPath: "/var/lib/containerd",
-			HostPath:      result.Volume.Mountpoint,
-		})
-	}
-}
-
-// RunTestEnv starts a Kubernetes API server and etcd server for use in the
-// tests and returns the Kubernetes configuration.
-func (h *Harness) RunTestEnv() (*rest.Config, error) {
-	started := time.Now()
-
-	testenv, err := kubernetes.StartTestEnvironment(h.TestSuite.AttachControlPlaneOutput)
-	if err != nil {
-		return nil, err
+		ctx := context.TODO()
+		if err := provider.Start(ctx); err != nil {
+			return nil, err
+		}
+		if len(h.TestSuite.KINDContainers) > 0 {
+			if err := provider.LoadImages(ctx, h.TestSuite.KINDContainers); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	h.T.Logf("started test environment (kube-apiserver and etcd) in %v, with following options:\n%s",
-		time.Since(started),
-		strings.Join(testenv.Environment.ControlPlane.GetAPIServer().Configure().AsStrings(nil), "\n"))
-	h.env = testenv.Environment
-
-	return testenv.Config, nil
+	return h.provider.Kubeconfig()
 }
 
 // Config returns the current Kubernetes configuration - either from the environment
@@ -246,16 +245,13 @@ func (h *Harness) Config() (*rest.Config, error) {
 	var err error
 	switch {
 	case h.TestSuite.Config != nil:
-		h.T.Log("running tests with passed rest config.")
+		h.GetLogger().Log("running tests with passed rest config.")
 		h.config = h.TestSuite.Config.RC
-	case h.TestSuite.StartControlPlane:
-		h.T.Log("running tests with a mocked control plane (kube-apiserver and etcd).")
-		h.config, err = h.RunTestEnv()
-	case h.TestSuite.StartKIND:
-		h.T.Log("running tests with KIND.")
-		h.config, err = h.RunKIND()
+	case h.clusterProvider() != "":
+		h.GetLogger().Logf("running tests with a cluster started by the %s provider.", h.clusterProvider())
+		h.config, err = h.startCluster(h.clusterProvider())
 	default:
-		h.T.Log("running tests using configured kubeconfig.")
+		h.GetLogger().Log("running tests using configured kubeconfig.")
 		h.config, err = kubernetes.GetConfig()
 		if err != nil {
 			return nil, err
@@ -265,6 +261,10 @@ func (h *Harness) Config() (*rest.Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if h.logLevel() >= testutils.LevelDebug {
+		logger := h.GetLogger()
+		h.config = kubernetes.TraceRequests(h.config, logger.Debugf, logger.Tracef)
+	}
 
 	// Newly started clusters aren't ready until default service account is ready.
 	// We need to wait until one is present. Otherwise, we sometimes hit an error such as:
@@ -274,11 +274,11 @@ func (h *Harness) Config() (*rest.Config, error) {
 	// account is not provided anyway?)
 	// We still do this when running inside a cluster, because the cluster kuttl is pointed *at* might
 	// be different from the cluster it is running *in*, and it does not hurt when it is the same cluster.
-	if !h.TestSuite.StartControlPlane {
+	if h.clusterProvider() != cluster.Envtest {
 		if err := h.waitForFunctionalCluster(); err != nil {
 			return nil, err
 		}
-		h.T.Logf("Successful connection to cluster at: %s", h.config.Host)
+		h.GetLogger().Logf("Successful connection to cluster at: %s", h.config.Host)
 	}
 
 	// The creation of the "kubeconfig" is necessary for out of cluster execution of kubectl,
@@ -328,7 +328,7 @@ func (h *Harness) waitForSA(name, namespace string) error {
 			return false, nil
 		}
 		if err != nil {
-			h.T.Logf("Error waiting for service account %s/%s (will retry): %v", namespace, name, err)
+			h.GetLogger().Debugf("Error waiting for service account %s/%s (will retry): %v", namespace, name, err)
 			return false, nil
 		}
 		return true, nil
@@ -389,7 +389,7 @@ func (h *Harness) DockerClient() (testutils.DockerClient, error) {
 func (h *Harness) RunTests() {
 	// cleanup after running tests
 	h.T.Cleanup(h.Stop)
-	h.T.Log("running tests")
+	h.GetLogger().Log("running tests")
 
 	testDirs := h.testPreProcessing()
 
@@ -399,9 +399,9 @@ func (h *Harness) RunTests() {
 	for _, testDir := range testDirs {
 		tempTests, err := h.LoadTests(testDir)
 		if err != nil {
-			h.T.Fatal(err)
+			h.failNow(err)
 		}
-		h.T.Logf("testsuite: %s has %d tests", testDir, len(tempTests))
+		h.GetLogger().Logf("testsuite: %s has %d tests", testDir, len(tempTests))
 		// array of test cases tied to testsuite (by testdir)
 		realTestSuite[testDir] = tempTests
 	}
@@ -415,10 +415,19 @@ func (h *Harness) RunTests() {
 					// elapsed time calculations.
 					t.Parallel()
 
-					test.SetLogger(testutils.NewTestLogger(t, test.GetName()))
+					progressCase := h.Progress.StartCase(test.GetName())
+					// Cleanups run in reverse order, so this one runs after those of the test case, which may fail it.
+					t.Cleanup(func() {
+						progressCase.Done(t.Failed())
+					})
+					test.SetProgress(progressCase)
+					logger := testutils.NewTestLogger(t, test.GetName(), h.logOptions()...)
+					test.SetLogger(logger)
 
 					if err := test.LoadTestSteps(); err != nil {
-						t.Fatal(err)
+						logger.Errorf("%v", err)
+						logger.Flush()
+						t.FailNow()
 					}
 
 					test.Run(t, suiteReport.NewTestReporter(test.GetName()))
@@ -427,7 +436,7 @@ func (h *Harness) RunTests() {
 		}
 	})
 
-	h.T.Log("run tests finished")
+	h.GetLogger().Log("run tests finished")
 }
 
 // testPreProcessing provides preprocessing bring all tests suites local if there are any refers to URLs.
@@ -438,22 +447,22 @@ func (h *Harness) testPreProcessing() []string {
 		if http.IsURL(dir) {
 			err := h.initTempPath()
 			if err != nil {
-				h.T.Fatal(err)
+				h.failNow(err)
 			}
 			client := http.NewClient()
-			h.T.Logf("downloading %s", dir)
+			h.GetLogger().Logf("downloading %s", dir)
 			// fresh temp dir created for each download to prevent overwriting
 			folder, err := os.MkdirTemp(h.tempPath, filepath.Base(dir))
 			if err != nil {
-				h.T.Fatal(err)
+				h.failNow(err)
 			}
 			filePath, err := client.DownloadFile(dir, folder)
 			if err != nil {
-				h.T.Fatal(err)
+				h.failNow(err)
 			}
 			err = file.UntarInPlace(filePath)
 			if err != nil {
-				h.T.Fatal(err)
+				h.failNow(err)
 			}
 			testDirs = append(testDirs, file.TrimExt(filePath))
 		} else {
@@ -465,13 +474,18 @@ func (h *Harness) testPreProcessing() []string {
 
 // Run the test harness - start the control plane and then run the tests.
 func (h *Harness) Run() {
+	// The logger is created before the signal handler uses it, concurrently with the tests.
+	logger := h.GetLogger()
 	// capture ctrl+c and provide clean up
 	go func() {
 		sigchan := make(chan os.Signal, 1)
 		signal.Notify(sigchan, os.Interrupt)
 		sig := <-sigchan
 		h.Stop()
-		h.T.Log("failed with", sig)
+		logger.Warnf("failed with %v", sig)
+		if err := h.Progress.Stop(); err != nil {
+			logger.Warnf("failed to show the progress of the tests: %v", err)
+		}
 		os.Exit(-1)
 	}()
 
@@ -493,7 +507,7 @@ func (h *Harness) Setup() {
 	}
 
 	h.report = report.NewSuiteCollection(h.TestSuite.Name)
-	h.T.Log("starting setup")
+	h.GetLogger().Log("starting setup")
 
 	cl, err := h.Client(false)
 	if err != nil {
@@ -534,7 +548,7 @@ func (h *Harness) Setup() {
 			h.fatal(fmt.Errorf("fatal error installing manifests: %v", err))
 		}
 	}
-	bgs, err := testutils.RunCommands(context.TODO(), h.GetLogger(), "default", h.TestSuite.Commands, "", h.TestSuite.Timeout, "")
+	bgs, err := testutils.RunCommands(context.TODO(), h.GetLogger(), "default", h.TestSuite.Commands, "", h.TestSuite.Timeout, "", nil)
 	// assign any background processes first for cleanup in case of any errors
 	h.bgProcesses = append(h.bgProcesses, bgs...)
 	if err != nil {
@@ -544,35 +558,32 @@ func (h *Harness) Setup() {
 
 // Stop the test environment and clean up the harness.
 func (h *Harness) Stop() {
-	h.T.Log("cleaning up")
+	h.GetLogger().Log("cleaning up")
 	if h.managerStopCh != nil {
 		close(h.managerStopCh)
 		h.managerStopCh = nil
 	}
 
-	if h.kind != nil {
-		logDir := filepath.Join(h.TestSuite.ArtifactsDir, fmt.Sprintf("kind-logs-%d", time.Now().Unix()))
-
-		h.T.Log("collecting cluster logs to", logDir)
-
-		if err := h.kind.CollectLogs(logDir); err != nil {
-			h.T.Log("error collecting kind cluster logs", err)
+	if h.provider != nil {
+		logDir := filepath.Join(h.TestSuite.ArtifactsDir, fmt.Sprintf("%s-logs-%d", h.clusterProvider(), time.Now().Unix()))
+		if err := h.provider.CollectLogs(context.TODO(), logDir); err != nil {
+			h.GetLogger().Warnf("error collecting cluster logs: %v", err)
 		}
 	}
 
 	if h.bgProcesses != nil {
 		for _, p := range h.bgProcesses {
-			h.T.Logf("killing process %q", p)
+			h.GetLogger().Debugf("killing process %q", p)
 			err := p.Process.Kill()
 			if err != nil {
-				h.T.Logf("bg process: %q kill error %v", p, err)
+				h.GetLogger().Warnf("bg process: %q kill error %v", p, err)
 			}
 			ps, err := p.Process.Wait()
 			if err != nil {
-				h.T.Logf("bg process: %q kill wait error %v", p, err)
+				h.GetLogger().Warnf("bg process: %q kill wait error %v", p, err)
 			}
 			if ps != nil {
-				h.T.Logf("bg process: %q exit code %v", p, ps.ExitCode())
+				h.GetLogger().Debugf("bg process: %q exit code %v", p, ps.ExitCode())
 			}
 		}
 	}
@@ -582,41 +593,32 @@ func (h *Harness) Stop() {
 	if h.TestSuite.SkipClusterDelete {
 		cwd, err := os.Getwd()
 		if err != nil {
-			h.T.Logf("issue getting work directory %v", err)
+			h.GetLogger().Warnf("issue getting work directory %v", err)
 		}
 		kubeconfig := filepath.Join(cwd, "kubeconfig")
 
-		h.T.Log("skipping cluster tear down")
-		h.T.Logf("to connect to the cluster, run: export KUBECONFIG=\"%s\"", kubeconfig)
+		h.GetLogger().Log("skipping cluster tear down")
+		h.GetLogger().Logf("to connect to the cluster, run: export KUBECONFIG=\"%s\"", kubeconfig)
 
 		return
 	}
 
-	if h.env != nil {
-		h.T.Log("tearing down mock control plane")
-		if err := h.env.Stop(); err != nil {
-			h.T.Log("error tearing down mock control plane", err)
+	if h.provider != nil {
+		h.GetLogger().Log("tearing down cluster")
+		if err := h.provider.Stop(context.TODO()); err != nil {
+			h.GetLogger().Warnf("error tearing down cluster: %v", err)
 		}
 
-		h.env = nil
+		h.provider = nil
 	}
 
-	h.T.Logf("removing temp folder: %q", h.tempPath)
+	h.GetLogger().Debugf("removing temp folder: %q", h.tempPath)
 	if err := os.RemoveAll(h.tempPath); err != nil {
-		h.T.Log("error removing temporary directory", err)
-	}
-
-	if h.kind != nil {
-		h.T.Log("tearing down kind cluster")
-		if err := h.kind.Stop(); err != nil {
-			h.T.Log("error tearing down kind cluster", err)
-		}
-
-		h.kind = nil
+		h.GetLogger().Warnf("error removing temporary directory: %v", err)
 	}
 }
 
-// wraps Test.Fatal in order to clean up harness
+// wraps failNow in order to clean up harness
 // fatal should NOT be used with a go routine, it is not thread safe.
 func (h *Harness) fatal(err error) {
 	// clean up on fatal in setup
@@ -626,11 +628,15 @@ func (h *Harness) fatal(err error) {
 		h.stopping = true
 		h.Stop()
 	}
-	h.T.Fatal(err)
+	h.failNow(err)
 }
 
-func (h *Harness) kubeconfigPath() string {
-	return filepath.Join(h.tempPath, "kubeconfig")
+// failNow logs err as an error through the logger of the harness, and stops the test.
+func (h *Harness) failNow(err error) {
+	logger := h.GetLogger()
+	logger.Errorf("%v", err)
+	logger.Flush()
+	h.T.FailNow()
 }
 
 // Report defines the report phase of the kuttl tests.  If report format is nil it is skipped.
@@ -658,23 +664,3 @@ func (h *Harness) reportName() string {
 	}
 	return "kuttl-report"
 }
-
-func (h *Harness) loadKindConfig(path string) (*kindConfig.Cluster, error) {
-	raw, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	cluster := &kindConfig.Cluster{}
-
-	decoder := yaml.NewDecoder(bytes.NewReader(raw))
-	decoder.SetStrict(true)
-
-	if err := decoder.Decode(cluster); err != nil {
-		return nil, err
-	}
-	if !kind.IsMinVersion(cluster.APIVersion) {
-		h.T.Logf("Warning: %q in %s is not a supported version.\n", cluster.APIVersion, path)
-	}
-	return cluster, nil
-}
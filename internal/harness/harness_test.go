@@ -2,14 +2,15 @@ package harness
 
 import (
 	"context"
-	"fmt"
-	"io"
+	"os"
 	"testing"
 
-	"github.com/moby/moby/api/types/volume"
-	"github.com/moby/moby/client"
 	"github.com/stretchr/testify/assert"
-	kindConfig "sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/kudobuilder/kuttl/internal/cluster"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
 )
 
 func TestGetTimeout(t *testing.T) {
@@ -28,64 +29,67 @@ func TestGetReportName(t *testing.T) {
 	assert.Equal(t, "special-kuttl-report", h.reportName())
 }
 
-type dockerMock struct {
-	ImageWriter *io.PipeWriter
-	imageReader *io.PipeReader
+type fakeProvider struct {
+	started bool
+	images  []string
+	stopped bool
 }
 
-func newDockerMock() *dockerMock {
-	reader, writer := io.Pipe()
+func (p *fakeProvider) Start(context.Context) error {
+	p.started = true
+	return nil
+}
 
-	return &dockerMock{
-		ImageWriter: writer,
-		imageReader: reader,
-	}
+func (p *fakeProvider) Kubeconfig() (*rest.Config, error) {
+	return &rest.Config{Host: "https://fake.example.com"}, nil
 }
 
-func (d *dockerMock) VolumeCreate(_ context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
-	return client.VolumeCreateResult{
-		Volume: volume.Volume{
-			Mountpoint: fmt.Sprintf("/var/lib/docker/data/%s", options.Name),
-		},
-	}, nil
+func (p *fakeProvider) LoadImages(_ context.Context, images []string) error {
+	p.images = images
+	return nil
 }
 
-func (d *dockerMock) ImageSave(context.Context, []string, ...client.ImageSaveOption) (client.ImageSaveResult, error) {
-	return d.imageReader, nil
+func (p *fakeProvider) CollectLogs(context.Context, string) error { return nil }
+
+func (p *fakeProvider) Stop(context.Context) error {
+	p.stopped = true
+	return nil
 }
 
-func TestAddNodeCaches(t *testing.T) {
-	h := Harness{
-		T:      t,
-		docker: newDockerMock(),
-	}
+func TestClusterProvider(t *testing.T) {
+	h := Harness{}
+	assert.Equal(t, "", h.clusterProvider())
+	h.TestSuite.StartKIND = true
+	assert.Equal(t, "kind", h.clusterProvider())
+	h.TestSuite = harness.TestSuite{StartControlPlane: true}
+	assert.Equal(t, "envtest", h.clusterProvider())
+	h.TestSuite.ClusterProvider = "k3d"
+	assert.Equal(t, "k3d", h.clusterProvider())
+}
 
-	kindCfg := &kindConfig.Cluster{}
-	h.addNodeCaches(h.docker, kindCfg)
-	assert.Nil(t, kindCfg.Nodes)
-
-	h.TestSuite.KINDNodeCache = true
-	h.addNodeCaches(h.docker, kindCfg)
-	assert.NotNil(t, kindCfg.Nodes)
-	assert.Equal(t, 1, len(kindCfg.Nodes))
-	assert.NotNil(t, kindCfg.Nodes[0].ExtraMounts)
-	assert.Equal(t, 1, len(kindCfg.Nodes[0].ExtraMounts))
-	assert.Equal(t, "/var/lib/containerd", kindCfg.Nodes[0].ExtraMounts[0].ContainerPath)
-	assert.Equal(t, "/var/lib/docker/data/kind-0", kindCfg.Nodes[0].ExtraMounts[0].HostPath)
-
-	kindCfg = &kindConfig.Cluster{
-		Nodes: []kindConfig.Node{
-			{},
-			{},
+func TestStartCluster(t *testing.T) {
+	provider := &fakeProvider{}
+	cluster.Register("fake", func(*harness.TestSuite, cluster.Options) (cluster.Provider, error) {
+		return provider, nil
+	})
+
+	h := Harness{
+		T: t,
+		TestSuite: harness.TestSuite{
+			ClusterProvider: "fake",
+			KINDContainers:  []string{"example.com/operator:dev"},
 		},
 	}
-
-	h.addNodeCaches(h.docker, kindCfg)
-	assert.NotNil(t, kindCfg.Nodes)
-	assert.Equal(t, 2, len(kindCfg.Nodes))
-	assert.NotNil(t, kindCfg.Nodes[0].ExtraMounts)
-	assert.Equal(t, 1, len(kindCfg.Nodes[0].ExtraMounts))
-	assert.Equal(t, "/var/lib/containerd", kindCfg.Nodes[0].ExtraMounts[0].ContainerPath)
-	assert.Equal(t, "/var/lib/docker/data/kind-0", kindCfg.Nodes[0].ExtraMounts[0].HostPath)
-	assert.Equal(t, "/var/lib/docker/data/kind-1", kindCfg.Nodes[1].ExtraMounts[0].HostPath)
+	t.Cleanup(func() {
+		assert.NoError(t, os.RemoveAll(h.tempPath))
+	})
+	cfg, err := h.startCluster(h.clusterProvider())
+	require.NoError(t, err)
+	assert.Equal(t, "https://fake.example.com", cfg.Host)
+	assert.True(t, provider.started)
+	assert.Equal(t, []string{"example.com/operator:dev"}, provider.images)
+
+	h.Stop()
+	assert.True(t, provider.stopped)
+	assert.Nil(t, h.provider)
 }
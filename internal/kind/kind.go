@@ -2,7 +2,6 @@ package kind
 
 import (
 	"context"
-	"testing"
 
 	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
@@ -58,12 +57,12 @@ func (k *Kind) IsRunning() bool {
 
 // AddContainers loads the named Docker containers into a KIND cluster.
 // The cluster must be running for this to work.
-func (k *Kind) AddContainers(docker testutils.DockerClient, containers []string, t *testing.T) error {
+func (k *Kind) AddContainers(docker testutils.DockerClient, containers []string, logger testutils.Logger) error {
 	if !k.IsRunning() {
 		panic("KIND cluster isn't running")
 	}
 
-	t.Logf("Adding Containers to KIND...\n")
+	logger.Logf("Adding Containers to KIND...")
 
 	nodes, err := k.Provider.ListNodes(k.context)
 	if err != nil {
@@ -72,7 +71,7 @@ func (k *Kind) AddContainers(docker testutils.DockerClient, containers []string,
 
 	for _, node := range nodes {
 		for _, container := range containers {
-			t.Logf("Add image %s to node %s\n", container, node.String())
+			logger.Logf("Add image %s to node %s", container, node.String())
 			if err := loadContainer(docker, node, container); err != nil {
 				return err
 			}
@@ -14,7 +14,12 @@ type level int32
 var verbosity level
 
 func SetFlags(flags *pflag.FlagSet) {
-	flags.VarP(&verbosity, "v", "v", "Logging verbosity level. 0=normal, 1=verbose, 2=detailed, 3+=trace.")
+	flags.VarP(&verbosity, "v", "v", "Logging verbosity level. 0=info, 1=debug, 2+=trace. KIND output is logged from 1.")
+}
+
+// Verbosity returns the logging verbosity level set with the -v flag.
+func Verbosity() int {
+	return int(verbosity)
 }
 
 func (l *level) Get() interface{} {
@@ -53,19 +58,19 @@ func (k kindLogger) V(level log.Level) log.InfoLogger {
 }
 
 func (k kindLogger) Warn(message string) {
-	k.l.Log(message)
+	k.l.Warnf("%s", message)
 }
 
 func (k kindLogger) Warnf(format string, args ...interface{}) {
-	k.l.Logf(format, args...)
+	k.l.Warnf(format, args...)
 }
 
 func (k kindLogger) Error(message string) {
-	k.l.Log(message)
+	k.l.Errorf("%s", message)
 }
 
 func (k kindLogger) Errorf(format string, args ...interface{}) {
-	k.l.Logf(format, args...)
+	k.l.Errorf(format, args...)
 }
 
 func (k kindLogger) Info(message string) {
@@ -0,0 +1,97 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// defaultContainerAnnotation names the container kubectl uses by default.
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// ExecResult is the outcome of a command executed in a container.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// newExecutor creates the executor streaming a command execution from url, in the same way as `kubectl exec`:
+// over WebSockets, falling back to SPDY for API servers which do not support them.
+// It is a variable so that tests can replace it.
+var newExecutor = func(cfg *rest.Config, u *url.URL) (remotecommand.Executor, error) {
+	spdyExec, err := remotecommand.NewSPDYExecutor(cfg, "POST", u)
+	if err != nil {
+		return nil, err
+	}
+	websocketExec, err := remotecommand.NewWebSocketExecutor(cfg, "GET", u.String())
+	if err != nil {
+		return nil, err
+	}
+	return remotecommand.NewFallbackExecutor(websocketExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+}
+
+// Exec runs command in container of the pod, and returns its output and exit code.
+// A non-zero exit code is not an error.
+func Exec(ctx context.Context, cfg *rest.Config, namespace, pod, container string, command []string) (ExecResult, error) {
+	cs, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	req := cs.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := newExecutor(cfg, req.URL())
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("creating executor for pod %s: %w", pod, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	result := ExecResult{Stdout: stdout.String(), Stderr: stderr.String()}
+
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		result.ExitCode = exitErr.ExitStatus()
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("executing command in container %s of pod %s: %w", container, pod, err)
+	}
+	return result, nil
+}
+
+// DefaultContainer returns the container commands are executed in when none is specified, in the same way as
+// `kubectl exec`: the one named by the kubectl.kubernetes.io/default-container annotation, or the first one.
+func DefaultContainer(pod *unstructured.Unstructured) (string, error) {
+	if name := pod.GetAnnotations()[defaultContainerAnnotation]; name != "" {
+		return name, nil
+	}
+	names, err := ContainerNames(pod)
+	if err != nil || len(names) == 0 {
+		return "", err
+	}
+	return names[0], nil
+}
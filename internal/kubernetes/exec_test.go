@@ -0,0 +1,103 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+type fakeExecutor struct {
+	url            *url.URL
+	stdout, stderr string
+	err            error
+}
+
+func (e *fakeExecutor) Stream(remotecommand.StreamOptions) error {
+	return errors.New("not implemented")
+}
+
+func (e *fakeExecutor) StreamWithContext(_ context.Context, options remotecommand.StreamOptions) error {
+	if _, err := options.Stdout.Write([]byte(e.stdout)); err != nil {
+		return err
+	}
+	if _, err := options.Stderr.Write([]byte(e.stderr)); err != nil {
+		return err
+	}
+	return e.err
+}
+
+func TestExec(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		executor *fakeExecutor
+		expected ExecResult
+		errMsg   string
+	}{
+		{
+			name:     "success",
+			executor: &fakeExecutor{stdout: "hello\n", stderr: "warning\n"},
+			expected: ExecResult{Stdout: "hello\n", Stderr: "warning\n"},
+		},
+		{
+			name:     "non-zero exit code",
+			executor: &fakeExecutor{stderr: "not found\n", err: utilexec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2}},
+			expected: ExecResult{Stderr: "not found\n", ExitCode: 2},
+		},
+		{
+			name:     "stream failure",
+			executor: &fakeExecutor{stdout: "partial", err: errors.New("connection reset")},
+			expected: ExecResult{Stdout: "partial"},
+			errMsg:   "executing command in container web of pod web-0: connection reset",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := newExecutor
+			t.Cleanup(func() { newExecutor = orig })
+			newExecutor = func(_ *rest.Config, u *url.URL) (remotecommand.Executor, error) {
+				tt.executor.url = u
+				return tt.executor, nil
+			}
+
+			result, err := Exec(t.Context(), &rest.Config{Host: "https://localhost:6443"}, "ns", "web-0", "web", []string{"cat", "/etc/hostname"})
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, "/api/v1/namespaces/ns/pods/web-0/exec", tt.executor.url.Path)
+			assert.Equal(t, url.Values{
+				"container": {"web"},
+				"command":   {"cat", "/etc/hostname"},
+				"stdout":    {"true"},
+				"stderr":    {"true"},
+			}, tt.executor.url.Query())
+		})
+	}
+}
+
+func TestDefaultContainer(t *testing.T) {
+	pod := NewPod("web", "default")
+	container, err := DefaultContainer(pod)
+	require.NoError(t, err)
+	assert.Empty(t, container)
+
+	pod.Object["spec"] = map[string]any{
+		"containers": []any{map[string]any{"name": "web"}, map[string]any{"name": "sidecar"}},
+	}
+	container, err = DefaultContainer(pod)
+	require.NoError(t, err)
+	assert.Equal(t, "web", container)
+
+	pod.SetAnnotations(map[string]string{"kubectl.kubernetes.io/default-container": "sidecar"})
+	container, err = DefaultContainer(pod)
+	require.NoError(t, err)
+	assert.Equal(t, "sidecar", container)
+}
@@ -13,6 +13,9 @@ import (
 	"k8s.io/client-go/testing"
 )
 
+// verbs are the verbs supported by all the resources of the fake discovery client.
+var verbs = metav1.Verbs{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}
+
 // DiscoveryClient returns a fake discovery client that is populated with some types for use in
 // unit tests.
 func DiscoveryClient() discovery.DiscoveryInterface {
@@ -22,40 +25,40 @@ func DiscoveryClient() discovery.DiscoveryInterface {
 				{
 					GroupVersion: corev1.SchemeGroupVersion.String(),
 					APIResources: []metav1.APIResource{
-						{Name: "pod", Namespaced: true, Kind: "Pod"},
-						{Name: "namespace", Namespaced: false, Kind: "Namespace"},
-						{Name: "service", Namespaced: true, Kind: "Service"},
+						{Name: "pod", Namespaced: true, Kind: "Pod", Verbs: verbs},
+						{Name: "namespace", Namespaced: false, Kind: "Namespace", Verbs: verbs},
+						{Name: "service", Namespaced: true, Kind: "Service", Verbs: verbs},
 					},
 				},
 				{
 					GroupVersion: appsv1.SchemeGroupVersion.String(),
 					APIResources: []metav1.APIResource{
-						{Name: "statefulset", Namespaced: true, Kind: "StatefulSet"},
-						{Name: "deployment", Namespaced: true, Kind: "Deployment"},
+						{Name: "statefulset", Namespaced: true, Kind: "StatefulSet", Verbs: verbs},
+						{Name: "deployment", Namespaced: true, Kind: "Deployment", Verbs: verbs},
 					},
 				},
 				{
 					GroupVersion: batchv1.SchemeGroupVersion.String(),
 					APIResources: []metav1.APIResource{
-						{Name: "job", Namespaced: true, Kind: "Job"},
+						{Name: "job", Namespaced: true, Kind: "Job", Verbs: verbs},
 					},
 				},
 				{
 					GroupVersion: v1beta1.SchemeGroupVersion.String(),
 					APIResources: []metav1.APIResource{
-						{Name: "job", Namespaced: true, Kind: "CronJob"},
+						{Name: "job", Namespaced: true, Kind: "CronJob", Verbs: verbs},
 					},
 				},
 				{
 					GroupVersion: apiextv1.SchemeGroupVersion.String(),
 					APIResources: []metav1.APIResource{
-						{Name: "customresourcedefinitions", Namespaced: false, Kind: "CustomResourceDefinition"},
+						{Name: "customresourcedefinitions", Namespaced: false, Kind: "CustomResourceDefinition", Verbs: verbs},
 					},
 				},
 				{
 					GroupVersion: apiextv1beta1.SchemeGroupVersion.String(),
 					APIResources: []metav1.APIResource{
-						{Name: "customresourcedefinitions", Namespaced: false, Kind: "CustomResourceDefinition"},
+						{Name: "customresourcedefinitions", Namespaced: false, Kind: "CustomResourceDefinition", Verbs: verbs},
 					},
 				},
 			},
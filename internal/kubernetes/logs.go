@@ -0,0 +1,102 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Pods returns the pod with the given name, or the pods matching selector if name is empty.
+// A missing pod is not an error.
+func Pods(ctx context.Context, cl client.Client, namespace, name string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	return getObjects(ctx, cl, corev1.SchemeGroupVersion.WithKind("Pod"), namespace, name, selector)
+}
+
+// PodLogs returns the logs of the given container of pod, or the concatenated logs of all its containers
+// if container is empty.
+func PodLogs(ctx context.Context, cs clientset.Interface, pod *unstructured.Unstructured, container string) (string, error) {
+	containers := []string{container}
+	if container == "" {
+		var err error
+		if containers, err = ContainerNames(pod); err != nil {
+			return "", err
+		}
+	}
+
+	var sb strings.Builder
+	for _, c := range containers {
+		logs, err := ContainerLogs(ctx, cs, pod.GetNamespace(), pod.GetName(), &corev1.PodLogOptions{Container: c})
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(logs)
+	}
+	return sb.String(), nil
+}
+
+// ContainerLogs returns the logs of the container of the pod selected by opts.
+func ContainerLogs(ctx context.Context, cs clientset.Interface, namespace, pod string, opts *corev1.PodLogOptions) (string, error) {
+	logs, err := cs.CoreV1().Pods(namespace).GetLogs(pod, opts).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting logs of container %s of pod %s: %w", opts.Container, pod, err)
+	}
+	return string(logs), nil
+}
+
+// ContainerNames returns the names of the containers of pod.
+func ContainerNames(pod *unstructured.Unstructured) ([]string, error) {
+	return containerNames(pod, "containers")
+}
+
+// AllContainerNames returns the names of the init containers and containers of pod,
+// in the same way as `kubectl logs --all-containers`.
+func AllContainerNames(pod *unstructured.Unstructured) ([]string, error) {
+	initContainers, err := containerNames(pod, "initContainers")
+	if err != nil {
+		return nil, err
+	}
+	containers, err := containerNames(pod, "containers")
+	if err != nil {
+		return nil, err
+	}
+	return append(initContainers, containers...), nil
+}
+
+// RestartCount returns the number of times the named init container or container of pod has been restarted.
+func RestartCount(pod *unstructured.Unstructured, container string) (int64, error) {
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, err := NestedMaps(pod.Object, "status", field)
+		if err != nil {
+			return 0, err
+		}
+		for _, status := range statuses {
+			if status["name"] == container {
+				count, _, err := unstructured.NestedInt64(status, "restartCount")
+				return count, err
+			}
+		}
+	}
+	return 0, nil
+}
+
+func containerNames(pod *unstructured.Unstructured, field string) ([]string, error) {
+	containers, err := NestedMaps(pod.Object, "spec", field)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(containers))
+	for _, container := range containers {
+		name, _, err := unstructured.NestedString(container, "name")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
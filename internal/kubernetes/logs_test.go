@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodLogs(t *testing.T) {
+	pod := NewPod("web", "default")
+	pod.Object["spec"] = map[string]any{
+		"containers": []any{map[string]any{"name": "web"}, map[string]any{"name": "sidecar"}},
+	}
+	names, err := ContainerNames(pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "sidecar"}, names)
+	names, err = AllContainerNames(pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "sidecar"}, names)
+
+	cs := fake.NewClientset()
+
+	// The fake clientset returns the same logs for every container.
+	logs, err := PodLogs(t.Context(), cs, pod, "web")
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", logs)
+
+	logs, err = PodLogs(t.Context(), cs, pod, "")
+	require.NoError(t, err)
+	assert.Equal(t, "fake logsfake logs", logs)
+}
+
+func TestAllContainerNames(t *testing.T) {
+	pod := NewPod("web", "default")
+	pod.Object["spec"] = map[string]any{
+		"initContainers": []any{map[string]any{"name": "migrate"}},
+		"containers":     []any{map[string]any{"name": "web"}},
+	}
+	names, err := ContainerNames(pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web"}, names)
+	names, err = AllContainerNames(pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"migrate", "web"}, names)
+
+	pod.Object["spec"] = map[string]any{"containers": []any{"web"}}
+	_, err = AllContainerNames(pod)
+	assert.ErrorContains(t, err, ".spec.containers[0] accessor error")
+}
+
+func TestRestartCount(t *testing.T) {
+	pod := NewPod("web", "default")
+	pod.Object["status"] = map[string]any{
+		"initContainerStatuses": []any{map[string]any{"name": "migrate", "restartCount": int64(0)}},
+		"containerStatuses":     []any{map[string]any{"name": "web", "restartCount": int64(2)}},
+	}
+	for container, expected := range map[string]int64{"migrate": 0, "web": 2, "sidecar": 0} {
+		count, err := RestartCount(pod, container)
+		require.NoError(t, err)
+		assert.Equal(t, expected, count, container)
+	}
+}
@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Lookup returns the content of the object with the given API version, kind, namespace and name.
+// If name is empty, all objects of the kind in the namespace (or in the whole cluster if namespace is empty)
+// are returned as a list object. If the object does not exist, an empty map is returned.
+func Lookup(ctx context.Context, cl client.Client, apiVersion, kind, namespace, name string) (map[string]any, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+
+	if name == "" {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gv.WithKind(kind + "List"))
+		if err := cl.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return map[string]any{}, nil
+			}
+			return nil, err
+		}
+		return list.UnstructuredContent(), nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(kind))
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return map[string]any{}, nil
+		}
+		return nil, err
+	}
+	return obj.UnstructuredContent(), nil
+}
+
+// ServerFacts returns the version of the API server and the sorted list of group versions it serves.
+func ServerFacts(dClient discovery.DiscoveryInterface) (string, []string, error) {
+	version, err := dClient.ServerVersion()
+	if err != nil {
+		return "", nil, fmt.Errorf("retrieving server version: %w", err)
+	}
+
+	groups, err := dClient.ServerGroups()
+	if err != nil {
+		return "", nil, fmt.Errorf("retrieving server groups: %w", err)
+	}
+
+	var apiVersions []string
+	for _, group := range groups.Groups {
+		for _, gv := range group.Versions {
+			apiVersions = append(apiVersions, gv.GroupVersion)
+		}
+	}
+	sort.Strings(apiVersions)
+
+	return version.GitVersion, apiVersions, nil
+}
@@ -0,0 +1,40 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kfake "github.com/kudobuilder/kuttl/internal/kubernetes/fake"
+)
+
+func TestLookup(t *testing.T) {
+	cl := fake.NewClientBuilder().WithObjects(testObj()).Build()
+	ctx := context.Background()
+
+	obj, err := Lookup(ctx, cl, "v1", "ConfigMap", "default", "cm")
+	require.NoError(t, err)
+	assert.Equal(t, "cm", obj["metadata"].(map[string]any)["name"])
+
+	obj, err = Lookup(ctx, cl, "v1", "ConfigMap", "default", "missing")
+	require.NoError(t, err)
+	assert.Empty(t, obj)
+
+	list, err := Lookup(ctx, cl, "v1", "ConfigMap", "default", "")
+	require.NoError(t, err)
+	assert.Len(t, list["items"], 1)
+
+	_, err = Lookup(ctx, cl, "a/b/c", "ConfigMap", "default", "cm")
+	assert.ErrorContains(t, err, "invalid apiVersion")
+}
+
+func TestServerFacts(t *testing.T) {
+	version, apiVersions, err := ServerFacts(kfake.DiscoveryClient())
+	require.NoError(t, err)
+	assert.NotEmpty(t, version)
+	assert.Contains(t, apiVersions, "apps/v1")
+	assert.IsNonDecreasing(t, apiVersions)
+}
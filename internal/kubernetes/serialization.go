@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/pmezard/go-difflib/difflib"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -129,6 +130,8 @@ func convertUnstructured(in client.Object) (client.Object, error) {
 		converted = &v1beta1.TestAssert{}
 	case "TestSuite":
 		converted = &v1beta1.TestSuite{}
+	case "TestCase":
+		converted = &v1beta1.TestCase{}
 	default:
 		return in, nil
 	}
@@ -188,6 +191,46 @@ func MarshalObjectJSON(o runtime.Object, w io.Writer) error {
 	return json.NewSerializer(json.DefaultMetaFactory, nil, nil, false).Encode(copied, w)
 }
 
+// MarshalSnapshotYAML marshals objects to a multi-document YAML stream, keeping their metadata
+// except for the managed fields. The values of Secrets are redacted, see RedactSecret.
+func MarshalSnapshotYAML(objects []unstructured.Unstructured, w io.Writer) error {
+	serializer := json.NewYAMLSerializer(json.DefaultMetaFactory, nil, nil)
+	for i := range objects {
+		copied := objects[i].DeepCopy()
+		copied.SetManagedFields(nil)
+		RedactSecret(copied)
+		if _, err := io.WriteString(w, "---\n"); err != nil {
+			return err
+		}
+		if err := serializer.Encode(copied, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RedactedValue replaces the values of Secrets in the snapshots written to the artifacts directory or the test output.
+const RedactedValue = "REDACTED"
+
+// RedactSecret replaces the values of the data and stringData of obj, if it is a Secret, keeping their keys.
+// The last applied configuration annotation, which may contain them, is redacted too.
+func RedactSecret(obj *unstructured.Unstructured) {
+	if obj.GroupVersionKind().GroupKind() != corev1.SchemeGroupVersion.WithKind("Secret").GroupKind() {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		if values, ok := obj.Object[field].(map[string]any); ok {
+			for key := range values {
+				values[key] = RedactedValue
+			}
+		}
+	}
+	if annotations := obj.GetAnnotations(); annotations[corev1.LastAppliedConfigAnnotation] != "" {
+		annotations[corev1.LastAppliedConfigAnnotation] = RedactedValue
+		obj.SetAnnotations(annotations)
+	}
+}
+
 // LoadYAMLFromFile loads all objects from a YAML file.
 func LoadYAMLFromFile(path string) ([]client.Object, error) {
 	opened, err := os.Open(path)
@@ -2,11 +2,14 @@ package kubernetes
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
 )
 
 func TestLoadYAML(t *testing.T) {
@@ -83,6 +86,25 @@ spec:
 	}, objs[1])
 }
 
+func TestLoadYAMLClusterProviderConfig(t *testing.T) {
+	objs, err := LoadYAML("kuttl-test.yaml", strings.NewReader(`
+apiVersion: kuttl.dev/v1beta1
+kind: TestSuite
+clusterProvider: k3d
+clusterProviderConfig:
+  servers: 1
+  image: rancher/k3s
+`))
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+
+	suite, ok := objs[0].(*harness.TestSuite)
+	require.True(t, ok)
+	assert.Equal(t, "k3d", suite.ClusterProvider)
+	require.NotNil(t, suite.ClusterProviderConfig)
+	assert.JSONEq(t, `{"servers": 1, "image": "rancher/k3s"}`, string(suite.ClusterProviderConfig.Raw))
+}
+
 func TestPrettyDiff(t *testing.T) {
 	actualObjs, err := LoadYAMLFromFile("test_data/prettydiff-actual.yaml")
 	assert.NoError(t, err)
@@ -0,0 +1,107 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NamespacedKinds returns the namespaced kinds which can be listed, in the version preferred by the server.
+// If names is not empty, only the kinds matching one of names are returned, in the same way as `kubectl get` types:
+// by kind, resource name, singular name or short name, optionally qualified with the group, e.g. `deployments.apps`.
+// It is an error if one of names does not match any kind.
+func NamespacedKinds(dClient discovery.DiscoveryInterface, names ...string) ([]schema.GroupVersionKind, error) {
+	groups, resourceLists, err := dClient.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+	preferred := map[string]bool{}
+	for _, group := range groups {
+		preferred[group.PreferredVersion.GroupVersion] = true
+	}
+
+	matched := make([]bool, len(names))
+	var kinds []schema.GroupVersionKind
+	for _, resourceList := range resourceLists {
+		if !preferred[resourceList.GroupVersion] {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resourceList.APIResources {
+			if !resource.Namespaced || strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			include := len(names) == 0
+			for i, name := range names {
+				if matchesResource(name, gv.Group, resource) {
+					matched[i] = true
+					include = true
+				}
+			}
+			if include {
+				kinds = append(kinds, gv.WithKind(resource.Kind))
+			}
+		}
+	}
+
+	for i, name := range names {
+		if !matched[i] {
+			return nil, fmt.Errorf("resource type %q not found", name)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i].String() < kinds[j].String()
+	})
+	return kinds, nil
+}
+
+// matchesResource reports whether name designates resource of group, in the same way as `kubectl get` types.
+func matchesResource(name, group string, resource metav1.APIResource) bool {
+	if n, g, qualified := strings.Cut(name, "."); qualified {
+		if g != group {
+			return false
+		}
+		name = n
+	}
+	for _, candidate := range append([]string{resource.Kind, resource.Name, resource.SingularName}, resource.ShortNames...) {
+		if candidate != "" && strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot returns the objects of the given kinds in namespace, matching selector, sorted by kind and name.
+// Kinds which cannot be listed are reported in the returned error, along with the objects of the other kinds.
+func Snapshot(ctx context.Context, cl client.Client, kinds []schema.GroupVersionKind, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+	var errs []error
+	for _, gvk := range kinds {
+		items, err := getObjects(ctx, cl, gvk, namespace, "", selector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing %v: %w", gvk, err))
+			continue
+		}
+		for i := range items {
+			items[i].SetGroupVersionKind(gvk)
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].GetName() < items[j].GetName()
+		})
+		objects = append(objects, items...)
+	}
+	return objects, errors.Join(errs...)
+}
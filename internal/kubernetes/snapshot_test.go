@@ -0,0 +1,122 @@
+package kubernetes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	k8sfake "github.com/kudobuilder/kuttl/internal/kubernetes/fake"
+)
+
+func TestNamespacedKinds(t *testing.T) {
+	dClient := k8sfake.DiscoveryClient()
+
+	kinds, err := NamespacedKinds(dClient)
+	require.NoError(t, err)
+	// Cluster-scoped kinds are excluded, and batch/v1beta1 is not the preferred version of its group.
+	assert.Equal(t, []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Pod"},
+		{Version: "v1", Kind: "Service"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		{Group: "batch", Version: "v1", Kind: "Job"},
+	}, kinds)
+
+	kinds, err = NamespacedKinds(dClient, "deployment.apps", "Pod")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Pod"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+	}, kinds)
+
+	_, err = NamespacedKinds(dClient, "deployment.batch")
+	assert.EqualError(t, err, `resource type "deployment.batch" not found`)
+}
+
+func TestMatchesResource(t *testing.T) {
+	deployments := metav1.APIResource{Name: "deployments", SingularName: "deployment", Kind: "Deployment", ShortNames: []string{"deploy"}}
+	for name, expected := range map[string]bool{
+		"Deployment":       true,
+		"deployments":      true,
+		"deployment":       true,
+		"deploy":           true,
+		"deployments.apps": true,
+		"deployments.v1":   false,
+		"pods":             false,
+	} {
+		assert.Equal(t, expected, matchesResource(name, "apps", deployments), name)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	web := WithLabels(t, NewPod("web", "ns"), map[string]string{"app": "web"})
+	db := WithLabels(t, NewPod("db", "ns"), map[string]string{"app": "db"})
+	other := NewPod("other", "other-ns")
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(web, db, other).Build()
+
+	pods := []schema.GroupVersionKind{{Version: "v1", Kind: "Pod"}}
+	objects, err := Snapshot(t.Context(), cl, pods, "ns", labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "db", objects[0].GetName())
+	assert.Equal(t, "web", objects[1].GetName())
+
+	selector, err := labels.Parse("app=web")
+	require.NoError(t, err)
+	objects, err = Snapshot(t.Context(), cl, pods, "ns", selector)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+
+	objects[0].SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	var buf bytes.Buffer
+	require.NoError(t, MarshalSnapshotYAML(objects, &buf))
+	assert.Contains(t, buf.String(), "---\napiVersion: v1\nkind: Pod\n")
+	assert.Contains(t, buf.String(), "resourceVersion:")
+	assert.NotContains(t, buf.String(), "managedFields")
+	// The objects themselves are left untouched.
+	assert.NotEmpty(t, objects[0].GetManagedFields())
+}
+
+func TestMarshalSnapshotYAMLRedactsSecrets(t *testing.T) {
+	secret := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":      "credentials",
+			"namespace": "ns",
+			"annotations": map[string]any{
+				corev1.LastAppliedConfigAnnotation: `{"stringData":{"password":"hunter2"}}`,
+			},
+		},
+		"data":       map[string]any{"token": "c2VjcmV0"},
+		"stringData": map[string]any{"password": "hunter2"},
+	}}
+	configMap := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "settings", "namespace": "ns"},
+		"data":       map[string]any{"token": "c2VjcmV0"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, MarshalSnapshotYAML([]unstructured.Unstructured{secret, configMap}, &buf))
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), "password: REDACTED")
+	assert.Contains(t, buf.String(), "kind: Secret\nmetadata:\n  annotations:\n    kubectl.kubernetes.io/last-applied-configuration: REDACTED\n")
+	// Only the values of Secrets are redacted.
+	assert.Contains(t, buf.String(), "token: c2VjcmV0")
+	assert.Contains(t, buf.String(), "token: REDACTED")
+	// The objects themselves are left untouched.
+	password, _, err := unstructured.NestedString(secret.Object, "stringData", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", password)
+}
@@ -0,0 +1,303 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ConditionMet reports whether obj has a status condition of the given type and status, and if not, why.
+func ConditionMet(obj *unstructured.Unstructured, conditionType, status string) (bool, string) {
+	condition, err := findCondition(obj, conditionType)
+	if err != nil {
+		return false, err.Error()
+	}
+	if condition == nil {
+		return false, fmt.Sprintf("condition %s not found", conditionType)
+	}
+	if condition["status"] == status {
+		return true, ""
+	}
+	if message, ok := condition["message"].(string); ok && message != "" {
+		return false, fmt.Sprintf("condition %s is %v: %s", conditionType, condition["status"], message)
+	}
+	return false, fmt.Sprintf("condition %s is %v", conditionType, condition["status"])
+}
+
+// findCondition returns the status condition of obj with the given type, or nil if there is none.
+func findCondition(obj *unstructured.Unstructured, conditionType string) (map[string]any, error) {
+	conditions, err := conditions(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	for _, condition := range conditions {
+		if condition["type"] == conditionType {
+			return condition, nil
+		}
+	}
+	return nil, nil
+}
+
+// conditions returns the status conditions of obj.
+func conditions(obj map[string]any) ([]map[string]any, error) {
+	return NestedMaps(obj, "status", "conditions")
+}
+
+// NestedMaps returns the list of maps of obj at path, which is empty if there is no value at path.
+// An error is returned if the value is not a list of maps. Like unstructured.NestedSlice, but without copying
+// the list, so that it also accepts objects converted from CEL values, which may hold types other than JSON ones.
+func NestedMaps(obj map[string]any, path ...string) ([]map[string]any, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(obj, path...)
+	if !found || err != nil {
+		return nil, err
+	}
+	items, ok := val.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s accessor error: %v is of the type %T, expected []interface{}", "."+strings.Join(path, "."), val, val)
+	}
+	maps := make([]map[string]any, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] accessor error: %v is of the type %T, expected map[string]interface{}",
+				"."+strings.Join(path, "."), i, item, item)
+		}
+		maps = append(maps, m)
+	}
+	return maps, nil
+}
+
+// rolloutStatusFields are the status fields RolloutComplete reads.
+var rolloutStatusFields = []string{
+	"replicas", "updatedReplicas", "availableReplicas", "readyReplicas",
+	"desiredNumberScheduled", "updatedNumberScheduled", "numberAvailable",
+}
+
+// RolloutComplete reports whether the rollout of a Deployment, StatefulSet or DaemonSet is complete, and if not, why,
+// in the same way as `kubectl rollout status`. An error is returned if the rollout cannot complete.
+func RolloutComplete(obj *unstructured.Unstructured) (bool, string, error) {
+	observedGeneration, _, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, "", err
+	}
+	if obj.GetGeneration() > observedGeneration {
+		return false, "waiting for the spec update to be observed", nil
+	}
+
+	status := make(map[string]int64, len(rolloutStatusFields))
+	for _, field := range rolloutStatusFields {
+		if status[field], _, err = unstructured.NestedInt64(obj.Object, "status", field); err != nil {
+			return false, "", err
+		}
+	}
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		return false, "", err
+	}
+	if !found {
+		replicas = 1
+	}
+
+	switch obj.GetKind() {
+	case "Deployment":
+		condition, err := findCondition(obj, "Progressing")
+		if err != nil {
+			return false, "", err
+		}
+		if condition["reason"] == "ProgressDeadlineExceeded" {
+			return false, "", errors.New("deployment exceeded its progress deadline")
+		}
+		switch {
+		case status["updatedReplicas"] < replicas:
+			return false, fmt.Sprintf("%d out of %d new replicas have been updated", status["updatedReplicas"], replicas), nil
+		case status["replicas"] > status["updatedReplicas"]:
+			return false, fmt.Sprintf("%d old replicas are pending termination", status["replicas"]-status["updatedReplicas"]), nil
+		case status["availableReplicas"] < status["updatedReplicas"]:
+			return false, fmt.Sprintf("%d of %d updated replicas are available", status["availableReplicas"], status["updatedReplicas"]), nil
+		}
+	case "StatefulSet":
+		strategy, _, err := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+		if err != nil {
+			return false, "", err
+		}
+		if strategy == "OnDelete" {
+			return true, "", nil
+		}
+		if status["readyReplicas"] < replicas {
+			return false, fmt.Sprintf("%d of %d pods are ready", status["readyReplicas"], replicas), nil
+		}
+		partition, _, err := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+		if err != nil {
+			return false, "", err
+		}
+		if partition > 0 {
+			if status["updatedReplicas"] < replicas-partition {
+				return false, fmt.Sprintf("%d of %d pods have been updated", status["updatedReplicas"], replicas-partition), nil
+			}
+			return true, "", nil
+		}
+		currentRevision, _, err := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		if err != nil {
+			return false, "", err
+		}
+		updateRevision, _, err := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		if err != nil {
+			return false, "", err
+		}
+		if currentRevision != updateRevision {
+			return false, fmt.Sprintf("%d of %d pods have been updated to revision %s", status["updatedReplicas"], replicas, updateRevision), nil
+		}
+	case "DaemonSet":
+		desired := status["desiredNumberScheduled"]
+		switch {
+		case status["updatedNumberScheduled"] < desired:
+			return false, fmt.Sprintf("%d of %d pods have been updated", status["updatedNumberScheduled"], desired), nil
+		case status["numberAvailable"] < desired:
+			return false, fmt.Sprintf("%d of %d updated pods are available", status["numberAvailable"], desired), nil
+		}
+	default:
+		return false, "", fmt.Errorf("rollout status is not supported for kind %s", obj.GetKind())
+	}
+	return true, "", nil
+}
+
+// JobComplete reports whether a Job has completed, and if not, why. An error is returned if the Job failed.
+func JobComplete(obj *unstructured.Unstructured) (bool, string, error) {
+	failed, err := findCondition(obj, "Failed")
+	if err != nil {
+		return false, "", err
+	}
+	if failed["status"] == "True" {
+		return false, "", errors.New("job failed")
+	}
+	complete, err := findCondition(obj, "Complete")
+	if err != nil {
+		return false, "", err
+	}
+	if complete["status"] == "True" {
+		return true, "", nil
+	}
+	succeeded, _, err := unstructured.NestedInt64(obj.Object, "status", "succeeded")
+	if err != nil {
+		return false, "", err
+	}
+	active, _, err := unstructured.NestedInt64(obj.Object, "status", "active")
+	if err != nil {
+		return false, "", err
+	}
+	return false, fmt.Sprintf("%d pods active, %d succeeded", active, succeeded), nil
+}
+
+// Ready reports whether obj is ready, and if not, why, for the kinds which have a notion of readiness:
+// the rollout of Deployments, StatefulSets and DaemonSets must be complete, Jobs must be complete,
+// and Pods must be ready or have succeeded. Objects of other kinds are always ready.
+func Ready(obj *unstructured.Unstructured) (bool, string) {
+	var ready bool
+	var reason string
+	var err error
+	switch obj.GroupVersionKind().GroupKind().String() {
+	case "Deployment.apps", "StatefulSet.apps", "DaemonSet.apps":
+		ready, reason, err = RolloutComplete(obj)
+	case "Job.batch":
+		ready, reason, err = JobComplete(obj)
+	case "Pod":
+		phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+		if err != nil {
+			return false, err.Error()
+		}
+		if phase == "Succeeded" {
+			return true, ""
+		}
+		return ConditionMet(obj, "Ready", "True")
+	default:
+		return true, ""
+	}
+	if err != nil {
+		return false, err.Error()
+	}
+	return ready, reason
+}
+
+// DescribeStatus describes the status conditions of obj and, for pods, the state of their containers,
+// in a similar way as `kubectl describe`.
+func DescribeStatus(obj *unstructured.Unstructured) (string, error) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+
+	conditions, err := conditions(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	if len(conditions) > 0 {
+		fmt.Fprintln(w, "Conditions:")
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, condition := range conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", stringField(condition, "type"), stringField(condition, "status"),
+				stringField(condition, "reason"), stringField(condition, "message"))
+		}
+	}
+
+	var statuses []map[string]any
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		s, err := NestedMaps(obj.Object, "status", field)
+		if err != nil {
+			return "", err
+		}
+		statuses = append(statuses, s...)
+	}
+	if len(statuses) > 0 {
+		fmt.Fprintln(w, "Containers:")
+		fmt.Fprintln(w, "  NAME\tREADY\tRESTARTS\tSTATE\tLAST STATE")
+		for _, status := range statuses {
+			state, _, err := unstructured.NestedMap(status, "state")
+			if err != nil {
+				return "", err
+			}
+			lastState, _, err := unstructured.NestedMap(status, "lastState")
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(w, "  %s\t%v\t%v\t%s\t%s\n", stringField(status, "name"), status["ready"], status["restartCount"],
+				describeContainerState(state), describeContainerState(lastState))
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// describeContainerState describes the state of a container, e.g. `waiting (CrashLoopBackOff: back-off 10s)`.
+func describeContainerState(state map[string]any) string {
+	for _, name := range []string{"waiting", "running", "terminated"} {
+		details, ok := state[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		var parts []string
+		if reason := stringField(details, "reason"); reason != "" {
+			parts = append(parts, reason)
+		}
+		if exitCode, ok := details["exitCode"]; ok {
+			parts = append(parts, fmt.Sprintf("exit code %v", exitCode))
+		}
+		if message := stringField(details, "message"); message != "" {
+			parts = append(parts, strings.Join(strings.Fields(message), " "))
+		}
+		if len(parts) == 0 {
+			return name
+		}
+		return fmt.Sprintf("%s (%s)", name, strings.Join(parts, ": "))
+	}
+	return ""
+}
+
+func stringField(m map[string]any, field string) string {
+	s, _ := m[field].(string)
+	return s
+}
@@ -0,0 +1,198 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func objectWith(kind string, generation int64, spec, status map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{"spec": spec, "status": status}}
+	obj.SetKind(kind)
+	obj.SetGeneration(generation)
+	return obj
+}
+
+func TestConditionMet(t *testing.T) {
+	pod := objectWith("Pod", 1, nil, map[string]any{
+		"conditions": []any{map[string]any{"type": "Ready", "status": "False", "message": "containers not ready"}},
+	})
+
+	met, msg := ConditionMet(pod, "Ready", "False")
+	assert.True(t, met)
+	assert.Empty(t, msg)
+
+	met, msg = ConditionMet(pod, "Ready", "True")
+	assert.False(t, met)
+	assert.Equal(t, "condition Ready is False: containers not ready", msg)
+
+	met, msg = ConditionMet(pod, "Initialized", "True")
+	assert.False(t, met)
+	assert.Equal(t, "condition Initialized not found", msg)
+
+	met, msg = ConditionMet(objectWith("Pod", 1, nil, map[string]any{"conditions": "Ready"}), "Ready", "True")
+	assert.False(t, met)
+	assert.Contains(t, msg, ".status.conditions accessor error")
+}
+
+func TestRolloutComplete(t *testing.T) {
+	for name, tt := range map[string]struct {
+		obj    *unstructured.Unstructured
+		done   bool
+		msg    string
+		errMsg string
+	}{
+		"deployment not observed": {
+			obj: objectWith("Deployment", 2, map[string]any{"replicas": int64(1)}, map[string]any{"observedGeneration": int64(1)}),
+			msg: "waiting for the spec update to be observed",
+		},
+		"deployment updating": {
+			obj: objectWith("Deployment", 1, map[string]any{"replicas": int64(3)}, map[string]any{
+				"observedGeneration": int64(1), "replicas": int64(3), "updatedReplicas": int64(1),
+			}),
+			msg: "1 out of 3 new replicas have been updated",
+		},
+		"deployment terminating old replicas": {
+			obj: objectWith("Deployment", 1, map[string]any{"replicas": int64(2)}, map[string]any{
+				"observedGeneration": int64(1), "replicas": int64(3), "updatedReplicas": int64(2),
+			}),
+			msg: "1 old replicas are pending termination",
+		},
+		"deployment complete": {
+			obj: objectWith("Deployment", 1, map[string]any{}, map[string]any{
+				"observedGeneration": int64(1), "replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(1),
+			}),
+			done: true,
+		},
+		"deployment past deadline": {
+			obj: objectWith("Deployment", 1, map[string]any{}, map[string]any{
+				"observedGeneration": int64(1),
+				"conditions":         []any{map[string]any{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}},
+			}),
+			errMsg: "exceeded its progress deadline",
+		},
+		"statefulset updating": {
+			obj: objectWith("StatefulSet", 1, map[string]any{"replicas": int64(2)}, map[string]any{
+				"observedGeneration": int64(1), "readyReplicas": int64(2), "updatedReplicas": int64(1),
+				"currentRevision": "db-1", "updateRevision": "db-2",
+			}),
+			msg: "1 of 2 pods have been updated to revision db-2",
+		},
+		"statefulset partitioned": {
+			obj: objectWith("StatefulSet", 1, map[string]any{
+				"replicas":       int64(3),
+				"updateStrategy": map[string]any{"type": "RollingUpdate", "rollingUpdate": map[string]any{"partition": int64(2)}},
+			}, map[string]any{
+				"observedGeneration": int64(1), "readyReplicas": int64(3), "updatedReplicas": int64(1),
+				"currentRevision": "db-1", "updateRevision": "db-2",
+			}),
+			done: true,
+		},
+		"daemonset unavailable": {
+			obj: objectWith("DaemonSet", 1, nil, map[string]any{
+				"observedGeneration": int64(1), "desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberAvailable": int64(2),
+			}),
+			msg: "2 of 3 updated pods are available",
+		},
+		"replicas of an unexpected type": {
+			obj:    objectWith("Deployment", 1, map[string]any{"replicas": 3.0}, map[string]any{"observedGeneration": int64(1)}),
+			errMsg: ".spec.replicas accessor error",
+		},
+		"conditions of an unexpected type": {
+			obj:    objectWith("Deployment", 1, nil, map[string]any{"observedGeneration": int64(1), "conditions": []any{"Progressing"}}),
+			errMsg: ".status.conditions[0] accessor error",
+		},
+		"unsupported kind": {
+			obj:    objectWith("Pod", 0, nil, nil),
+			errMsg: "not supported for kind Pod",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			done, msg, err := RolloutComplete(tt.obj)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.done, done)
+			assert.Equal(t, tt.msg, msg)
+		})
+	}
+}
+
+func TestJobComplete(t *testing.T) {
+	done, msg, err := JobComplete(objectWith("Job", 1, nil, map[string]any{"active": int64(1)}))
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "1 pods active, 0 succeeded", msg)
+
+	done, _, err = JobComplete(objectWith("Job", 1, nil, map[string]any{
+		"conditions": []any{map[string]any{"type": "Complete", "status": "True"}},
+	}))
+	assert.NoError(t, err)
+	assert.True(t, done)
+
+	_, _, err = JobComplete(objectWith("Job", 1, nil, map[string]any{
+		"conditions": []any{map[string]any{"type": "Failed", "status": "True"}},
+	}))
+	assert.EqualError(t, err, "job failed")
+}
+
+func TestReady(t *testing.T) {
+	deployment := objectWith("Deployment", 1, map[string]any{"replicas": int64(1)}, map[string]any{
+		"observedGeneration": int64(1), "replicas": int64(1), "updatedReplicas": int64(1),
+	})
+	deployment.SetAPIVersion("apps/v1")
+	ready, msg := Ready(deployment)
+	assert.False(t, ready)
+	assert.Equal(t, "0 of 1 updated replicas are available", msg)
+
+	job := objectWith("Job", 1, nil, map[string]any{
+		"conditions": []any{map[string]any{"type": "Failed", "status": "True"}},
+	})
+	job.SetAPIVersion("batch/v1")
+	ready, msg = Ready(job)
+	assert.False(t, ready)
+	assert.Equal(t, "job failed", msg)
+
+	pod := objectWith("Pod", 1, nil, map[string]any{"phase": "Succeeded"})
+	pod.SetAPIVersion("v1")
+	ready, _ = Ready(pod)
+	assert.True(t, ready)
+
+	// Kinds without a notion of readiness, including those of other groups, are always ready.
+	custom := objectWith("Deployment", 2, nil, nil)
+	custom.SetAPIVersion("example.com/v1")
+	ready, _ = Ready(custom)
+	assert.True(t, ready)
+}
+
+func TestDescribeStatus(t *testing.T) {
+	pod := objectWith("Pod", 1, nil, map[string]any{
+		"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "ContainersNotReady", "message": "containers with unready status: [web]"},
+		},
+		"containerStatuses": []any{map[string]any{
+			"name":         "web",
+			"ready":        false,
+			"restartCount": int64(3),
+			"state": map[string]any{
+				"waiting": map[string]any{"reason": "CrashLoopBackOff", "message": "back-off 40s restarting failed container"},
+			},
+			"lastState": map[string]any{
+				"terminated": map[string]any{"reason": "Error", "exitCode": int64(1)},
+			},
+		}},
+	})
+	description, err := DescribeStatus(pod)
+	require.NoError(t, err)
+	assert.Equal(t, `Conditions:
+  TYPE    STATUS   REASON               MESSAGE
+  Ready   False    ContainersNotReady   containers with unready status: [web]
+Containers:
+  NAME   READY   RESTARTS   STATE                                                                  LAST STATE
+  web    false   3          waiting (CrashLoopBackOff: back-off 40s restarting failed container)   terminated (Error: exit code 1)
+`, description)
+}
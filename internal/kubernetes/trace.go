@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Logf is a printf-like logging function, e.g. the Debugf method of a logger.
+type Logf func(format string, args ...any)
+
+// TraceRequests returns a copy of cfg whose clients log each API request: its method, URL, response status and
+// duration with debugf, and the request and response headers with tracef. The credentials in the headers are redacted.
+func TraceRequests(cfg *rest.Config, debugf, tracef Logf) *rest.Config {
+	traced := rest.CopyConfig(cfg)
+	traced.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &requestTracer{rt: rt, debugf: debugf, tracef: tracef}
+	})
+	return traced
+}
+
+type requestTracer struct {
+	rt     http.RoundTripper
+	debugf Logf
+	tracef Logf
+}
+
+func (t *requestTracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.tracef("%s %s request headers: %s", req.Method, req.URL, formatHeaders(req.Header))
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.debugf("%s %s failed in %v: %v", req.Method, req.URL, duration, err)
+		return resp, err
+	}
+	t.debugf("%s %s %s in %v", req.Method, req.URL, resp.Status, duration)
+	t.tracef("%s %s response headers: %s", req.Method, req.URL, formatHeaders(resp.Header))
+	return resp, nil
+}
+
+// formatHeaders formats headers sorted by name, redacting credentials.
+func formatHeaders(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(headers.Values(name), ", ")
+		if name == "Authorization" || name == "Cookie" || name == "Set-Cookie" {
+			value = "<redacted>"
+		}
+		formatted = append(formatted, name+": "+value)
+	}
+	return strings.Join(formatted, "; ")
+}
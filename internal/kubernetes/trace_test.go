@@ -0,0 +1,40 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestTraceRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var debug, trace []string
+	cfg := &rest.Config{Host: server.URL, BearerToken: "secret"}
+	traced := TraceRequests(cfg,
+		func(format string, args ...any) { debug = append(debug, fmt.Sprintf(format, args...)) },
+		func(format string, args ...any) { trace = append(trace, fmt.Sprintf(format, args...)) })
+	assert.Nil(t, cfg.WrapTransport, "the config is copied")
+
+	client, err := rest.HTTPClientFor(traced)
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL + "/api/v1/namespaces/ns/pods/web")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, debug, 1)
+	assert.Regexp(t, `^GET http://127\.0\.0\.1:\d+/api/v1/namespaces/ns/pods/web 404 Not Found in \d+m?s$`, debug[0])
+	require.Len(t, trace, 2)
+	assert.Contains(t, trace[0], "request headers: Authorization: <redacted>")
+	assert.NotContains(t, trace[0], "secret")
+	assert.Contains(t, trace[1], "response headers: Content-Length: 0; Content-Type: application/json;")
+}
@@ -7,6 +7,8 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,3 +39,93 @@ func WaitForDelete(cl client.Client, toDelete []client.Object, duration time.Dur
 	}
 	return nil
 }
+
+// StateCheck reports whether obj has reached a state, and if not, why. An error aborts the wait.
+type StateCheck func(ctx context.Context, obj *unstructured.Unstructured) (done bool, msg string, err error)
+
+// WaitForState waits for the object of kind gvk with the given name, or for all the objects matching selector,
+// in namespace to reach the state reported by check, up to duration. At least one object must match selector.
+// Retries on transient errors.
+func WaitForState(ctx context.Context, cl client.Client, gvk schema.GroupVersionKind, namespace, name string, selector labels.Selector,
+	check StateCheck, duration time.Duration) error {
+	lastCheckMsg := ""
+	err := wait.PollUntilContextTimeout(ctx, time.Second, duration, true, func(ctx context.Context) (bool, error) {
+		objects, err := getObjects(ctx, cl, gvk, namespace, name, selector)
+		if err != nil {
+			lastCheckMsg = fmt.Sprintf("getting %v failed: %v", gvk, err)
+			return false, nil
+		}
+		if len(objects) == 0 {
+			lastCheckMsg = fmt.Sprintf("no %v matched", gvk)
+			return false, nil
+		}
+		for _, obj := range objects {
+			done, msg, err := check(ctx, &obj)
+			if err != nil {
+				return false, fmt.Errorf("%v %s: %w", gvk, obj.GetName(), err)
+			}
+			if !done {
+				lastCheckMsg = fmt.Sprintf("%v %s: %s", gvk, obj.GetName(), msg)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			return fmt.Errorf("timed out (result of last check was: %q): %w", lastCheckMsg, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// WaitForAbsence waits for the object of kind gvk with the given name, or for all the objects matching selector,
+// to be absent from namespace, up to duration. Retries on transient errors.
+func WaitForAbsence(ctx context.Context, cl client.Client, gvk schema.GroupVersionKind, namespace, name string, selector labels.Selector,
+	duration time.Duration) error {
+	lastCheckMsg := ""
+	err := wait.PollUntilContextTimeout(ctx, time.Second, duration, true, func(ctx context.Context) (bool, error) {
+		objects, err := getObjects(ctx, cl, gvk, namespace, name, selector)
+		if err != nil {
+			lastCheckMsg = fmt.Sprintf("checking existence of %v failed: %v", gvk, err)
+			return false, nil
+		}
+		if len(objects) > 0 {
+			lastCheckMsg = fmt.Sprintf("%v %s still exists", gvk, objects[0].GetName())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("timed out (result of last check was: %q): %w", lastCheckMsg, err)
+	}
+	return nil
+}
+
+// getObjects returns the object with the given name, or the objects matching selector if name is empty.
+// A missing named object results in an empty list.
+func getObjects(ctx context.Context, cl client.Client, gvk schema.GroupVersionKind, namespace, name string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	if name != "" {
+		obj := unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &obj); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []unstructured.Unstructured{obj}, nil
+	}
+
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if selector != nil && !selector.Empty() {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	if err := cl.List(ctx, &list, opts...); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
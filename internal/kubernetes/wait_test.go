@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -84,3 +85,47 @@ func TestWaitForDelete_PersistentErrorTimesOut(t *testing.T) {
 	assert.ErrorContains(t, err, "failed: persistent API error")
 	assert.NotContains(t, err.Error(), "initial transient error")
 }
+
+func TestWaitForState(t *testing.T) {
+	ready := testObj()
+	ready.SetLabels(map[string]string{"app": "a"})
+	ready.Object["data"] = map[string]any{"ready": "true"}
+	notReady := testObj()
+	notReady.SetName("other")
+	notReady.SetLabels(map[string]string{"app": "b"})
+	cl := fake.NewClientBuilder().WithObjects(ready, notReady).Build()
+
+	isReady := func(_ context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+		value, _, err := unstructured.NestedString(obj.Object, "data", "ready")
+		return value == "true", "not ready", err
+	}
+	gvk := ready.GroupVersionKind()
+
+	require.NoError(t, WaitForState(t.Context(), cl, gvk, "default", "cm", nil, isReady, time.Second))
+	require.NoError(t, WaitForState(t.Context(), cl, gvk, "default", "", labels.SelectorFromSet(labels.Set{"app": "a"}), isReady, time.Second))
+
+	err := WaitForState(t.Context(), cl, gvk, "default", "", labels.Everything(), isReady, time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "/v1, Kind=ConfigMap other: not ready")
+
+	err = WaitForState(t.Context(), cl, gvk, "default", "missing", nil, isReady, time.Second)
+	assert.ErrorContains(t, err, "no /v1, Kind=ConfigMap matched")
+
+	err = WaitForState(t.Context(), cl, gvk, "default", "cm", nil, func(context.Context, *unstructured.Unstructured) (bool, string, error) {
+		return false, "", fmt.Errorf("failed for good")
+	}, time.Minute)
+	assert.EqualError(t, err, "/v1, Kind=ConfigMap cm: failed for good")
+}
+
+func TestWaitForAbsence(t *testing.T) {
+	obj := testObj()
+	obj.SetLabels(map[string]string{"app": "a"})
+	cl := fake.NewClientBuilder().WithObjects(obj).Build()
+	gvk := obj.GroupVersionKind()
+
+	require.NoError(t, WaitForAbsence(t.Context(), cl, gvk, "default", "missing", nil, time.Second))
+	require.NoError(t, WaitForAbsence(t.Context(), cl, gvk, "default", "", labels.SelectorFromSet(labels.Set{"app": "b"}), time.Second))
+
+	err := WaitForAbsence(t.Context(), cl, gvk, "default", "", labels.SelectorFromSet(labels.Set{"app": "a"}), time.Second)
+	assert.ErrorContains(t, err, "/v1, Kind=ConfigMap cm still exists")
+}
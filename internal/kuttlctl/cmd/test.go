@@ -5,18 +5,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/term"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/kudobuilder/kuttl/internal/cluster"
 	"github.com/kudobuilder/kuttl/internal/harness"
 	"github.com/kudobuilder/kuttl/internal/kind"
 	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	"github.com/kudobuilder/kuttl/internal/progress"
 	"github.com/kudobuilder/kuttl/internal/report"
 	testutils "github.com/kudobuilder/kuttl/internal/utils"
 	harnessApi "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
@@ -64,6 +68,10 @@ func newTestCmd() *cobra.Command { //nolint:gocyclo
 	reportFormat := ""
 	reportName := "kuttl-report"
 	reportGranularity := "kuttl-report"
+	logFormat := ""
+	quiet := false
+	showProgress := false
+	clusterProvider := ""
 	namespace := ""
 	suppress := []string{}
 	templateVarsStrings := map[string]string{}
@@ -118,6 +126,12 @@ For more detailed documentation, visit: https://github.com/kudobuilder/kuttl`,
 				}
 			}
 
+			for i, collector := range options.Collectors {
+				if err := collector.Validate(); err != nil {
+					return fmt.Errorf("invalid collector %d in %s: %w", i, configPath, err)
+				}
+			}
+
 			// Override configuration file options with any command line flags if they are set.
 			if isSet(flags, "crd-dir") {
 				options.CRDDir = crdDir
@@ -152,10 +166,36 @@ For more detailed documentation, visit: https://github.com/kudobuilder/kuttl`,
 				options.KINDContext = harnessApi.DefaultKINDContext
 			}
 
+			if isSet(flags, "cluster-provider") {
+				options.ClusterProvider = clusterProvider
+			}
+
 			if options.StartControlPlane && options.StartKIND {
 				return errors.New("only one of --start-control-plane and --start-kind can be set")
 			}
 
+			// The built-in providers are the same as their flags, which imply more settings below.
+			switch options.ClusterProvider {
+			case "":
+			case cluster.Envtest:
+				if options.StartKIND {
+					return fmt.Errorf("cluster provider %q cannot be used with --start-kind", options.ClusterProvider)
+				}
+				options.StartControlPlane = true
+			case cluster.Kind:
+				if options.StartControlPlane {
+					return fmt.Errorf("cluster provider %q cannot be used with --start-control-plane", options.ClusterProvider)
+				}
+				options.StartKIND = true
+			default:
+				if options.StartControlPlane || options.StartKIND {
+					return fmt.Errorf("cluster provider %q cannot be used with --start-control-plane or --start-kind", options.ClusterProvider)
+				}
+				if !slices.Contains(cluster.Names(), options.ClusterProvider) {
+					return fmt.Errorf("unknown cluster provider %q, must be one of %s", options.ClusterProvider, strings.Join(cluster.Names(), ", "))
+				}
+			}
+
 			// after control-plane && start=kind check
 			if options.AttachControlPlaneOutput && !options.StartControlPlane {
 				return errors.New("only use --attach-control-plane-output with --start-control-plane")
@@ -197,6 +237,30 @@ For more detailed documentation, visit: https://github.com/kudobuilder/kuttl`,
 				options.ReportGranularity = reportGranularity
 			}
 
+			if isSet(flags, "log-format") {
+				options.LogFormat = logFormat
+			}
+			if _, err := testutils.ParseLogFormat(options.LogFormat); err != nil {
+				return err
+			}
+
+			if isSet(flags, "progress") {
+				options.Progress = showProgress
+			}
+
+			if quiet && isSet(flags, "v") {
+				return errors.New("only one of --quiet and -v can be set")
+			}
+			if quiet {
+				options.LogLevel = testutils.LevelWarn.String()
+			}
+			if isSet(flags, "v") {
+				options.LogLevel = testutils.LevelForVerbosity(kind.Verbosity()).String()
+			}
+			if _, err := testutils.ParseLevel(options.LogLevel); err != nil {
+				return err
+			}
+
 			if isSet(flags, "artifacts-dir") {
 				options.ArtifactsDir = artifactsDir
 			}
@@ -244,18 +308,29 @@ For more detailed documentation, visit: https://github.com/kudobuilder/kuttl`,
 			return nil
 		},
 		Run: func(*cobra.Command, []string) {
-			testutils.RunTests("kuttl", testToRun, options.Parallel, func(t *testing.T) {
+			display := progressDisplay(options.Progress)
+			if err := display.Capture(); err != nil {
+				log.Println("not showing the progress of the tests:", err)
+				display = nil
+			}
+			m := testutils.MainStart("kuttl", testToRun, options.Parallel, func(t *testing.T) {
 				h := harness.Harness{
 					TestSuite:    options,
 					T:            t,
 					RunLabels:    runLabels.AsLabelSet(),
 					TemplateVars: templateVarsParsed,
+					Progress:     display,
 				}
 				ctrl.SetLogger(testr.NewWithOptions(t, testr.Options{
 					LogTimestamp: true,
 				}))
 				h.Run()
 			})
+			code := m.Run()
+			if err := display.Stop(); err != nil {
+				log.Println("failed to show the progress of the tests:", err)
+			}
+			os.Exit(code)
 		},
 	}
 
@@ -269,18 +344,22 @@ For more detailed documentation, visit: https://github.com/kudobuilder/kuttl`,
 	testCmd.Flags().StringVar(&mockControllerFile, "control-plane-config", "", "Path to file to load controller-runtime APIServer configuration arguments (only useful when --startControlPlane).")
 	testCmd.Flags().BoolVar(&startKIND, "start-kind", false, "Start a KIND cluster for the tests (cannot be used with --start-control-plane).")
 	testCmd.Flags().StringVar(&kindConfig, "kind-config", "", "Specify the KIND configuration file path (implies --start-kind, cannot be used with --start-control-plane).")
+	testCmd.Flags().StringVar(&clusterProvider, "cluster-provider", "", "Name of the provider which starts the cluster for the tests, e.g. envtest, kind or script.")
 	testCmd.Flags().StringVar(&kindContext, "kind-context", "", "Specify the KIND context name to use (default: kind).")
-	testCmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "", "Directory to output kind logs to (if not specified, the current working directory).")
+	testCmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "", "Directory to output kind logs and collector output to (if not specified, the current working directory).")
 	testCmd.Flags().BoolVar(&skipDelete, "skip-delete", false, "If set, do not delete resources created during tests (helpful for debugging test failures, implies --skip-cluster-delete).")
-	testCmd.Flags().BoolVar(&skipClusterDelete, "skip-cluster-delete", false, "If set, do not delete the mocked control plane or kind cluster.")
+	testCmd.Flags().BoolVar(&skipClusterDelete, "skip-cluster-delete", false, "If set, do not delete the mocked control plane, kind cluster, or cluster of the cluster provider.")
 	// The default value here is only used for the help message. The default is actually enforced in RunTests.
 	testCmd.Flags().IntVar(&parallel, "parallel", 8, "The maximum number of tests to run at once.")
 	testCmd.Flags().IntVar(&timeout, "timeout", 30, "The timeout to use as default for TestSuite configuration.")
 	testCmd.Flags().StringVar(&reportFormat, "report", "", "Specify JSON|XML for report.  Report location determined by --artifacts-dir.")
 	testCmd.Flags().StringVar(&reportName, "report-name", "kuttl-report", "Name for the report.  Report location determined by --artifacts-dir and report file type determined by --report.")
 	testCmd.Flags().StringVar(&reportGranularity, "report-granularity", "step", "Report granularity. Can be 'step' (default) or 'test'.")
+	testCmd.Flags().StringVar(&logFormat, "log-format", "text", "Format of the test output. Can be 'text' (default) or 'json'.")
 	testCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to use for tests. Provided namespaces must exist prior to running tests.")
-	testCmd.Flags().StringSliceVar(&suppress, "suppress-log", []string{}, "Suppress logging for these kinds of logs (events).")
+	testCmd.Flags().BoolVar(&showProgress, "progress", false, "Show a live view of the running tests when the standard output is a terminal.")
+	testCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only log errors and warnings (cannot be used with -v).")
+	testCmd.Flags().StringSliceVar(&suppress, "suppress-log", []string{}, "Suppress logging for these kinds of logs (events, commands, diffs, resources).")
 	testCmd.Flags().Var(&runLabels, "test-run-labels", "Labels to use for this test run.")
 	testCmd.Flags().StringToStringVar(&templateVarsStrings, "template-var", map[string]string{}, "Template variables to use for this test run.")
 	// This cannot be a global flag because internal/utils.RunTests calls flag.Parse which barfs on unknown top-level flags.
@@ -290,6 +369,20 @@ For more detailed documentation, visit: https://github.com/kudobuilder/kuttl`,
 	return testCmd
 }
 
+// progressDisplay returns the live view of the running tests, or nil if it is not enabled
+// or the standard output is not a terminal.
+func progressDisplay(enabled bool) *progress.Display {
+	fd := int(os.Stdout.Fd())
+	if !enabled || !term.IsTerminal(fd) {
+		return nil
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		width = 0
+	}
+	return progress.New(os.Stdout, width)
+}
+
 func reportType(ftype report.Type) string {
 	switch ftype {
 	case report.JSON:
@@ -0,0 +1,257 @@
+// Package progress provides a live view of the running test cases for interactive terminals.
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// maxCases is the maximum number of running test cases listed in the view.
+const maxCases = 10
+
+// Display is a live view of the running test cases, with their current step and elapsed time, and the counts of
+// passed and failed test cases. The view is drawn at the bottom of a terminal, below the output of the tests, and
+// redrawn every second. The output of the tests must be written through the Display, see Capture.
+// The methods of a nil Display do nothing, so that it can be passed around whether the view is enabled or not.
+type Display struct {
+	term io.Writer
+	// Width of the terminal, which the lines of the view are truncated to, so that they do not wrap.
+	width int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	start   time.Time
+	running []*Case
+	passed  int
+	failed  int
+	// Number of lines of the view drawn at the bottom of the terminal.
+	lines int
+	// Output of the tests not yet terminated by a newline.
+	partial []byte
+	// First error drawing the view, after which it is no longer drawn.
+	err error
+
+	// stop stops the capture started by Capture, once.
+	stop     func() error
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// Case is a test case in the view.
+type Case struct {
+	display *Display
+	name    string
+	step    string
+	start   time.Time
+}
+
+// New returns a Display drawn on term, which is width columns wide. If width is not positive, lines are not truncated.
+func New(term io.Writer, width int) *Display {
+	return &Display{term: term, width: width, now: time.Now, start: time.Now()}
+}
+
+// StartCase adds a running test case to the view.
+func (d *Display) StartCase(name string) *Case {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := &Case{display: d, name: name, start: d.now()}
+	d.running = append(d.running, c)
+	d.redraw()
+	return c
+}
+
+// Step sets the step the test case is running.
+func (c *Case) Step(step string) {
+	if c == nil {
+		return
+	}
+	c.display.mu.Lock()
+	defer c.display.mu.Unlock()
+	c.step = step
+	c.display.redraw()
+}
+
+// Done removes the test case from the view, counting it as passed or failed.
+func (c *Case) Done(failed bool) {
+	if c == nil {
+		return
+	}
+	d := c.display
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, running := range d.running {
+		if running == c {
+			d.running = append(d.running[:i], d.running[i+1:]...)
+			break
+		}
+	}
+	if failed {
+		d.failed++
+	} else {
+		d.passed++
+	}
+	d.redraw()
+}
+
+// Write writes the output of the tests above the view. Incomplete lines are buffered until they are terminated.
+func (d *Display) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.partial = append(d.partial, p...)
+	end := bytes.LastIndexByte(d.partial, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	d.clear()
+	if _, err := d.term.Write(d.partial[:end+1]); err != nil {
+		return 0, err
+	}
+	d.partial = append([]byte{}, d.partial[end+1:]...)
+	d.draw()
+	return len(p), nil
+}
+
+// Capture redirects the standard output of the process through the Display, and redraws the view every second,
+// until Stop is called.
+func (d *Display) Capture() error {
+	if d == nil {
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(d, r)
+		if err != nil {
+			// Keep reading, so that writing to the standard output does not block.
+			_, discardErr := io.Copy(io.Discard, r)
+			err = errors.Join(err, discardErr)
+		}
+		copied <- errors.Join(err, r.Close())
+	}()
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.mu.Lock()
+				d.redraw()
+				d.mu.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	d.stop = func() error {
+		os.Stdout = stdout
+		closeErr := w.Close()
+		var copyErr error
+		if closeErr == nil {
+			// The copy only ends once the pipe is closed.
+			copyErr = <-copied
+		}
+		close(done)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.clear()
+		var writeErr error
+		if len(d.partial) > 0 {
+			_, writeErr = d.term.Write(append(d.partial, '\n'))
+			d.partial = nil
+		}
+		return errors.Join(closeErr, copyErr, writeErr, d.err)
+	}
+	return nil
+}
+
+// Stop stops the capture of the standard output started by Capture: it restores the standard output, writes the
+// output of the tests which is left, and removes the view from the terminal. It returns the errors of writing the
+// output and drawing the view. Only the first call has an effect, so that it can be called on interrupt too.
+func (d *Display) Stop() error {
+	if d == nil || d.stop == nil {
+		return nil
+	}
+	d.stopOnce.Do(func() {
+		d.stopErr = d.stop()
+	})
+	return d.stopErr
+}
+
+func (d *Display) redraw() {
+	d.clear()
+	d.draw()
+}
+
+// clear removes the view from the terminal, leaving the cursor where it started.
+func (d *Display) clear() {
+	if d.err != nil || d.lines == 0 {
+		return
+	}
+	_, d.err = fmt.Fprintf(d.term, "\x1b[%dF\x1b[J", d.lines)
+	d.lines = 0
+}
+
+func (d *Display) draw() {
+	if d.err != nil {
+		return
+	}
+	var view string
+	if view, d.err = d.render(); d.err != nil {
+		return
+	}
+	d.lines = strings.Count(view, "\n")
+	_, d.err = io.WriteString(d.term, view)
+}
+
+// render returns the lines of the view.
+func (d *Display) render() (string, error) {
+	now := d.now()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %d running, %d passed, %d failed, %s elapsed\n", len(d.running), d.passed, d.failed,
+		now.Sub(d.start).Round(time.Second))
+	tw := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	for i, c := range d.running {
+		if i == maxCases {
+			fmt.Fprintf(tw, "    ... and %d more\n", len(d.running)-maxCases)
+			break
+		}
+		step := c.step
+		if step == "" {
+			step = "setup"
+		}
+		fmt.Fprintf(tw, "    %s\t%s\t%s\n", c.name, step, now.Sub(c.start).Round(time.Second))
+	}
+	if err := tw.Flush(); err != nil {
+		return "", err
+	}
+	if d.width <= 0 {
+		return buf.String(), nil
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if line := []rune(strings.TrimSuffix(line, "\n")); len(line) >= d.width {
+			lines[i] = string(line[:d.width-1]) + "\n"
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
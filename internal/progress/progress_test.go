@@ -0,0 +1,96 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplay(t *testing.T) {
+	var term bytes.Buffer
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	d := New(&term, 0)
+	d.now = func() time.Time { return now }
+	d.start = start
+
+	install := d.StartCase("install")
+	now = now.Add(5 * time.Second)
+	upgrade := d.StartCase("upgrade-with-a-long-name")
+	install.Step("1-check")
+	now = now.Add(10 * time.Second)
+	term.Reset()
+
+	fmt.Fprint(d, "=== RUN   kuttl/harness/")
+	assert.Empty(t, term.String(), "incomplete lines are buffered")
+	fmt.Fprint(d, "install\n")
+	assert.Equal(t, "\x1b[3F\x1b[J"+
+		"=== RUN   kuttl/harness/install\n"+
+		"--- 2 running, 0 passed, 0 failed, 15s elapsed\n"+
+		"    install                    1-check   15s\n"+
+		"    upgrade-with-a-long-name   setup     10s\n", term.String())
+
+	install.Done(true)
+	upgrade.Done(false)
+	d.StartCase("cleanup").Step("0-delete")
+	term.Reset()
+	d.redraw()
+	assert.Equal(t, "\x1b[2F\x1b[J"+
+		"--- 1 running, 1 passed, 1 failed, 15s elapsed\n"+
+		"    cleanup   0-delete   0s\n", term.String())
+}
+
+func TestDisplayWidth(t *testing.T) {
+	var term bytes.Buffer
+	d := New(&term, 20)
+	d.StartCase("a-test-case-with-a-long-name")
+	view, err := d.render()
+	require.NoError(t, err)
+	assert.Equal(t, "--- 1 running, 0 pa\n    a-test-case-wit\n", view)
+}
+
+func TestNilDisplay(t *testing.T) {
+	var d *Display
+	c := d.StartCase("install")
+	c.Step("0-setup")
+	c.Done(false)
+	require.NoError(t, d.Capture())
+	assert.NoError(t, d.Stop())
+}
+
+func TestCapture(t *testing.T) {
+	var term bytes.Buffer
+	d := New(&term, 0)
+	require.NoError(t, d.Capture())
+	fmt.Println("=== RUN   kuttl")
+	fmt.Print("--- PASS: kuttl")
+	require.NoError(t, d.Stop())
+	// Stopping again, e.g. on interrupt, has no effect.
+	require.NoError(t, d.Stop())
+
+	assert.Equal(t, "=== RUN   kuttl\n"+
+		"--- 0 running, 0 passed, 0 failed, 0s elapsed\n"+
+		"\x1b[1F\x1b[J--- PASS: kuttl\n", term.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("terminal is gone")
+}
+
+func TestDisplayWriteError(t *testing.T) {
+	d := New(failingWriter{}, 0)
+	c := d.StartCase("install")
+	c.Step("0-setup")
+	c.Done(false)
+	assert.EqualError(t, d.err, "terminal is gone")
+
+	_, err := fmt.Fprintln(d, "=== RUN   kuttl")
+	assert.EqualError(t, err, "terminal is gone")
+}
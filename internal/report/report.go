@@ -65,6 +65,9 @@ type Testcase struct {
 	Assertions int `xml:"assertions,attr" json:"assertions,omitempty"`
 	// Failure defines a failure in this Testcase.
 	Failure *Failure `xml:"failure" json:"failure,omitempty"`
+	// SystemOut lists the artifacts collected by this Testcase, one [[ATTACHMENT|<path>]] line each,
+	// which is the convention CI systems such as Jenkins and GitLab use to attach files to JUnit test cases.
+	SystemOut string `xml:"system-out,omitempty" json:"systemOut,omitempty"`
 
 	// end is not reported.  It is used to calculate duration times for testcase and testsuite.
 	end time.Time
@@ -120,6 +123,7 @@ type Testsuites struct {
 type StepReporter interface {
 	Failure(message string, errors ...error)
 	AddAssertions(i int)
+	AddAttachments(paths ...string)
 }
 
 // TestReporter is an interface for reporting status of a test.
@@ -243,11 +247,12 @@ func (ts *Testsuite) NewTestReporter(name string) TestReporter {
 }
 
 type stepReport struct {
-	name       string
-	failed     bool
-	failureMsg string
-	errors     []error
-	assertions int
+	name        string
+	failed      bool
+	failureMsg  string
+	errors      []error
+	assertions  int
+	attachments []string
 }
 
 func (s *stepReport) Failure(message string, errors ...error) {
@@ -260,11 +265,18 @@ func (s *stepReport) AddAssertions(i int) {
 	s.assertions += i
 }
 
+func (s *stepReport) AddAttachments(paths ...string) {
+	s.attachments = append(s.attachments, paths...)
+}
+
 func (s *stepReport) populate(testCase *Testcase) {
 	if s.failed {
 		testCase.Failure = NewFailure(s.failureMsg, s.errors)
 	}
 	testCase.Assertions += s.assertions
+	for _, path := range s.attachments {
+		testCase.SystemOut += fmt.Sprintf("[[ATTACHMENT|%s]]\n", path)
+	}
 }
 
 type testReporter struct {
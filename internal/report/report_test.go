@@ -98,3 +98,22 @@ AssertionError`,
 	}
 	assert.Equal(t, string(gjson), jout, "for golden file: %s", jsonFile)
 }
+
+func TestStepReportAttachments(t *testing.T) {
+	suite := NewSuite("suite", "test")
+	reporter := suite.NewTestReporter("case")
+	reporter.Step("step 0-setup").AddAttachments("/artifacts/suite/case/0-setup/0-command.log")
+	step := reporter.Step("step 1-check")
+	step.AddAttachments("/artifacts/suite/case/1-check/0-events-ns.log", "/artifacts/suite/case/1-check/1-command.log")
+	step.Failure("failed in step 1-check")
+	reporter.Done()
+
+	require.Len(t, suite.Testcases, 1)
+	assert.Equal(t, "[[ATTACHMENT|/artifacts/suite/case/0-setup/0-command.log]]\n"+
+		"[[ATTACHMENT|/artifacts/suite/case/1-check/0-events-ns.log]]\n"+
+		"[[ATTACHMENT|/artifacts/suite/case/1-check/1-command.log]]\n", suite.Testcases[0].SystemOut)
+
+	x, err := xml.Marshal(suite.Testcases[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(x), "<system-out>[[ATTACHMENT|/artifacts/suite/case/0-setup/0-command.log]]")
+}
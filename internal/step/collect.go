@@ -0,0 +1,245 @@
+package step
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kfile "github.com/kudobuilder/kuttl/internal/file"
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	eventutils "github.com/kudobuilder/kuttl/internal/utils/events"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// Collect runs the collectors of the TestAssert, followed by the default collectors of the test case and test suite,
+// which run after a step with the outcome given by failed. They write their output to the log and to files in the
+// artifacts directory of the step, which are recorded in Artifacts. Each collector is given the step timeout.
+// Collection failures are logged.
+func (s *Step) Collect(namespace string, failed bool) {
+	var collectors []*harness.TestCollector
+	if s.Assert != nil {
+		collectors = s.Assert.Collectors
+	}
+	assertCollectors := len(collectors)
+	collectors = slices.Concat(collectors, s.DefaultCollectors)
+	if len(collectors) == 0 {
+		return
+	}
+	for i, collector := range collectors {
+		// The default collectors are validated when they are loaded. They are shared by the test cases running in
+		// parallel, so they must not be modified by validating them again.
+		if i < assertCollectors {
+			if err := collector.Validate(); err != nil {
+				s.Logger.Warnf("skipping invalid assertion collector: %v", err)
+				continue
+			}
+		}
+		if !collector.RunsAfter(failed) {
+			continue
+		}
+		s.Logger.Logf("collecting log output for %s", collector.String())
+		if err := s.collectWithTimeout(namespace, i, collector); err != nil {
+			s.Logger.Errorf("post assert collector failure: %v", err)
+		}
+	}
+	s.Logger.Flush()
+}
+
+// collectWithTimeout runs a collector within the step timeout, if any.
+func (s *Step) collectWithTimeout(namespace string, index int, collector *harness.TestCollector) error {
+	ctx := context.TODO()
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.Timeout)*time.Second)
+		defer cancel()
+	}
+	return s.collect(ctx, namespace, index, collector)
+}
+
+func (s *Step) collect(ctx context.Context, namespace string, index int, collector *harness.TestCollector) error {
+	if collector.Namespace != "" {
+		namespace = collector.Namespace
+	}
+	switch collector.Type {
+	case harness.CollectorTypePod:
+		return s.collectPodLogs(ctx, namespace, index, collector)
+	case harness.CollectorTypeEvents:
+		return s.collectEvents(ctx, namespace, index, collector)
+	case harness.CollectorTypeCommand:
+		return s.collectCommand(ctx, namespace, index, collector)
+	case harness.CollectorTypeResources:
+		return s.collectResources(ctx, namespace, index, collector)
+	default:
+		return fmt.Errorf("collector type %q unknown", collector.Type)
+	}
+}
+
+// collectPodLogs collects the logs of the selected containers of the selected pods, in the same way as
+// `kubectl logs --prefix`.
+func (s *Step) collectPodLogs(ctx context.Context, namespace string, index int, collector *harness.TestCollector) error {
+	cs, err := s.clientset()
+	if err != nil {
+		return err
+	}
+	cl, err := s.Client(false)
+	if err != nil {
+		return err
+	}
+	selector, err := labels.Parse(collector.Selector)
+	if err != nil {
+		return err
+	}
+	pods, err := kubernetes.Pods(ctx, cl, namespace, collector.Pod, selector)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found for %s", collector.String())
+	}
+
+	var tailLines *int64
+	if tail := int64(collector.TailLines()); tail >= 0 {
+		tailLines = &tail
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		containers := []string{collector.Container}
+		if collector.Container == "" {
+			if containers, err = kubernetes.AllContainerNames(&pod); err != nil {
+				errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+				continue
+			}
+		}
+		for _, container := range containers {
+			logs, err := kubernetes.ContainerLogs(ctx, cs, pod.GetNamespace(), pod.GetName(), &corev1.PodLogOptions{
+				Container: container,
+				TailLines: tailLines,
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			prefix := fmt.Sprintf("[pod/%s/%s] ", pod.GetName(), container)
+			for _, line := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+				fmt.Fprintf(s.Logger, "%s%s\n", prefix, line)
+			}
+			name := fmt.Sprintf("%d-logs-%s-%s-%s.log", index, pod.GetNamespace(), pod.GetName(), container)
+			if err := s.writeArtifact(name, []byte(logs)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// collectEvents collects the events of the namespace, or the named event, in the same way as `kubectl get events`.
+func (s *Step) collectEvents(ctx context.Context, namespace string, index int, collector *harness.TestCollector) error {
+	cl, err := s.Client(false)
+	if err != nil {
+		return err
+	}
+	items, err := eventutils.List(ctx, cl, namespace)
+	if err != nil {
+		return err
+	}
+	if collector.Pod != "" {
+		items = slices.DeleteFunc(items, func(event corev1.Event) bool {
+			return event.Name != collector.Pod
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := eventutils.WriteTable(&buf, items); err != nil {
+		return err
+	}
+	if _, err := s.Logger.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return s.writeArtifact(fmt.Sprintf("%d-events-%s.log", index, namespace), buf.Bytes())
+}
+
+// collectResources dumps the objects of the selected kinds in the namespace as YAML, without their managed fields.
+// The objects are listed in the log, and dumped to a file in the artifacts directory of the step.
+func (s *Step) collectResources(ctx context.Context, namespace string, index int, collector *harness.TestCollector) error {
+	dClient, err := s.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+	cl, err := s.Client(false)
+	if err != nil {
+		return err
+	}
+	selector, err := labels.Parse(collector.Selector)
+	if err != nil {
+		return err
+	}
+	kinds, err := kubernetes.NamespacedKinds(dClient, collector.Kinds...)
+	if err != nil {
+		return err
+	}
+
+	objects, snapshotErr := kubernetes.Snapshot(ctx, cl, kinds, namespace, selector)
+	for i := range objects {
+		s.Logger.Log(kubernetes.ResourceID(&objects[i]))
+	}
+	var buf bytes.Buffer
+	if err := kubernetes.MarshalSnapshotYAML(objects, &buf); err != nil {
+		return errors.Join(snapshotErr, err)
+	}
+	name := fmt.Sprintf("%d-resources-%s.yaml", index, namespace)
+	if err := s.writeArtifact(name, buf.Bytes()); err != nil {
+		return errors.Join(snapshotErr, err)
+	}
+	s.Logger.Logf("dumped %d objects of %d kinds to %s", len(objects), len(kinds), name)
+	return snapshotErr
+}
+
+func (s *Step) collectCommand(ctx context.Context, namespace string, index int, collector *harness.TestCollector) error {
+	var buf bytes.Buffer
+	out := io.MultiWriter(testutils.CommandOutput(s.Logger), &buf)
+	_, err := testutils.RunCommand(ctx, namespace, *collector.Command(), s.Dir, out, out, s.Logger, s.Timeout, s.Kubeconfig, s.Variables)
+	if artifactErr := s.writeArtifact(fmt.Sprintf("%d-command.log", index), buf.Bytes()); artifactErr != nil {
+		return errors.Join(err, artifactErr)
+	}
+	return err
+}
+
+// artifactsDir returns the directory the artifacts of the step are written to,
+// or an empty string if no artifacts directory is configured.
+func (s *Step) artifactsDir() string {
+	if s.TemplateEnv.ArtifactsDir == "" {
+		return ""
+	}
+	return filepath.Join(kfile.ArtifactsDir(s.TemplateEnv.ArtifactsDir, s.TemplateEnv.SuiteName, s.TemplateEnv.CaseName), s.String())
+}
+
+// writeArtifact writes content to the named file in the artifacts directory of the step, if any,
+// and records its path in Artifacts.
+// Collectors prefix the names of their files with their index, so that they do not overwrite each other's.
+func (s *Step) writeArtifact(name string, content []byte) error {
+	dir := s.artifactsDir()
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating artifacts directory: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	s.Artifacts = append(s.Artifacts, path)
+	return nil
+}
@@ -0,0 +1,72 @@
+package step
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+
+	eventutils "github.com/kudobuilder/kuttl/internal/utils/events"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// CheckAssertEvents checks the events emitted so far against the events assertions of the TestAssert.
+func (s *Step) CheckAssertEvents(ctx context.Context, namespace string) []error {
+	if len(s.Assert.Events) == 0 {
+		return nil
+	}
+	cl, err := s.Client(false)
+	if err != nil {
+		return []error{err}
+	}
+
+	var testErrors []error
+	listed := map[string][]corev1.Event{}
+	for _, e := range s.Assert.Events {
+		ns := namespace
+		if e.Namespace != "" {
+			ns = e.Namespace
+		}
+		events, ok := listed[ns]
+		if !ok {
+			if events, err = eventutils.ListFromAnyAPI(ctx, cl, ns); err != nil {
+				testErrors = append(testErrors, fmt.Errorf("%s: listing events in namespace %s: %w", e.String(), ns, err))
+				continue
+			}
+			listed[ns] = events
+		}
+
+		minCount := max(e.MinCount, 1)
+		if count := countEvents(e, events); count < minCount {
+			testErrors = append(testErrors, fmt.Errorf("%s: found %d occurrences in namespace %s, expected at least %d",
+				e.String(), count, ns, minCount))
+		}
+	}
+	return testErrors
+}
+
+// countEvents returns the number of occurrences of the events matching e, counting the repetitions of each event.
+func countEvents(e harness.TestEvent, events []corev1.Event) int {
+	var message *regexp.Regexp
+	if e.Message != "" {
+		// The regex was validated when the step was loaded.
+		message = regexp.MustCompile(e.Message)
+	}
+	count := 0
+	for i := range events {
+		event := &events[i]
+		object := event.InvolvedObject
+		switch {
+		case e.InvolvedObject.APIVersion != "" && e.InvolvedObject.APIVersion != object.APIVersion,
+			e.InvolvedObject.Kind != "" && e.InvolvedObject.Kind != object.Kind,
+			e.InvolvedObject.Name != "" && e.InvolvedObject.Name != object.Name,
+			e.Type != "" && e.Type != event.Type,
+			e.Reason != "" && e.Reason != event.Reason,
+			message != nil && !message.MatchString(event.Message):
+			continue
+		}
+		count += max(int(event.Count), 1)
+	}
+	return count
+}
@@ -0,0 +1,214 @@
+package step
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// maxReportedLogLines is the number of trailing lines of pod logs included in the errors of failed logs assertions.
+const maxReportedLogLines = 50
+
+// Exec executes the commands declared in the TestStep in pods, in order. Each command is executed once,
+// and all of them must finish before ctx is done.
+func (s *Step) Exec(ctx context.Context, namespace string) error {
+	if s.Step == nil || len(s.Step.Exec) == 0 {
+		return nil
+	}
+	for _, e := range s.Step.Exec {
+		s.Logger.Logf("running %s", e.String())
+		if err := s.exec(ctx, namespace, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckAssertExec executes the exec assertions of the TestAssert and checks their exit code and output.
+// All of them must finish within timeout seconds, which is the remaining assert timeout.
+func (s *Step) CheckAssertExec(ctx context.Context, namespace string, timeout int) []error {
+	if len(s.Assert.Exec) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	var testErrors []error
+	for _, e := range s.Assert.Exec {
+		if err := s.exec(ctx, namespace, e); err != nil {
+			testErrors = append(testErrors, err)
+		}
+	}
+	return testErrors
+}
+
+// CheckAssertLogs checks the logs of pods against the logs assertions of the TestAssert.
+func (s *Step) CheckAssertLogs(ctx context.Context, namespace string) []error {
+	if len(s.Assert.Logs) == 0 {
+		return nil
+	}
+	cs, err := s.clientset()
+	if err != nil {
+		return []error{err}
+	}
+
+	var testErrors []error
+	for _, l := range s.Assert.Logs {
+		pods, err := s.pods(ctx, namespace, l.Namespace, l.Pod, l.Selector)
+		if err != nil {
+			testErrors = append(testErrors, fmt.Errorf("%s: %w", l.String(), err))
+			continue
+		}
+		for _, pod := range pods {
+			logs, err := kubernetes.PodLogs(ctx, cs, &pod, l.Container)
+			if err != nil {
+				testErrors = append(testErrors, fmt.Errorf("%s: %w", l.String(), err))
+				continue
+			}
+			if err := testutils.MatchOutput("logs", &l.OutputMatcher, logs); err != nil {
+				testErrors = append(testErrors, fmt.Errorf("%s: pod %s: %w\nlast %d lines of logs were:\n%s",
+					l.String(), pod.GetName(), err, maxReportedLogLines, lastLines(logs, maxReportedLogLines)))
+			}
+		}
+	}
+	return testErrors
+}
+
+// exec executes a command in the pods selected by e. A run of the command is limited by its own timeout,
+// within the step or remaining assert timeout of ctx.
+func (s *Step) exec(ctx context.Context, namespace string, e harness.TestExec) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: no time left to run the command: %w", e.String(), context.Cause(ctx))
+	}
+	cfg, err := s.restConfig()
+	if err != nil {
+		return err
+	}
+	pods, err := s.pods(ctx, namespace, e.Namespace, e.Pod, e.Selector)
+	if err != nil {
+		return fmt.Errorf("%s: %w", e.String(), err)
+	}
+
+	runCtx := ctx
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, time.Duration(e.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		container := e.Container
+		if container == "" {
+			var err error
+			if container, err = kubernetes.DefaultContainer(&pod); err != nil {
+				errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+				continue
+			}
+		}
+		result, err := kubernetes.Exec(runCtx, cfg, pod.GetNamespace(), pod.GetName(), container, e.Command)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			// Only this run of the command timed out, not the whole assert, so it should be retried.
+			errs = append(errs, fmt.Errorf("pod %s: did not finish within its %d sec timeout", pod.GetName(), e.Timeout))
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+			continue
+		}
+		if !e.SkipLogOutput {
+			s.Logger.Logf("pod %s: exit code %d\nstdout:\n%s\nstderr:\n%s", pod.GetName(), result.ExitCode, result.Stdout, result.Stderr)
+		}
+		if err := checkExecResult(e, result); err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s failed: %w", e.String(), errors.Join(errs...))
+	}
+	return nil
+}
+
+// checkExecResult checks the exit code and output of a command against the expectations of e.
+func checkExecResult(e harness.TestExec, result kubernetes.ExecResult) error {
+	expectedExitCode := 0
+	if e.ExitCode != nil {
+		expectedExitCode = *e.ExitCode
+	}
+
+	var errs []error
+	if result.ExitCode != expectedExitCode {
+		err := fmt.Errorf("exited with code %d, expected %d", result.ExitCode, expectedExitCode)
+		if !e.SkipLogOutput && e.Stderr == nil {
+			err = fmt.Errorf("%w\nstderr was:\n%s", err, result.Stderr)
+		}
+		errs = append(errs, err)
+	}
+	if err := testutils.MatchOutput("stdout", e.Stdout, result.Stdout); err != nil {
+		if !e.SkipLogOutput {
+			err = fmt.Errorf("%w\nstdout was:\n%s", err, result.Stdout)
+		}
+		errs = append(errs, err)
+	}
+	if err := testutils.MatchOutput("stderr", e.Stderr, result.Stderr); err != nil {
+		if !e.SkipLogOutput {
+			err = fmt.Errorf("%w\nstderr was:\n%s", err, result.Stderr)
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// pods returns the pod with the given name, or the pods matching selector, in namespaceOverride if set.
+// It is an error if no pod is found.
+func (s *Step) pods(ctx context.Context, namespace, namespaceOverride, name, selector string) ([]unstructured.Unstructured, error) {
+	if namespaceOverride != "" {
+		namespace = namespaceOverride
+	}
+	cl, err := s.Client(false)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubernetes.Pods(ctx, cl, namespace, name, sel)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("pod %s not found in namespace %s", name, namespace)
+		}
+		return nil, fmt.Errorf("no pods matching selector %s found in namespace %s", selector, namespace)
+	}
+	return pods, nil
+}
+
+// restConfig returns the REST config of the step's cluster, for the APIs which require streaming.
+func (s *Step) restConfig() (*rest.Config, error) {
+	if s.Config == nil {
+		return nil, fmt.Errorf("no REST config available for step %s", s.String())
+	}
+	return s.Config()
+}
+
+// lastLines returns the last n lines of text.
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -131,6 +131,15 @@ func TestAssertExpressions(t *testing.T) {
 		{
 			name: "check expression for ephemeral namespace",
 		},
+		{
+			name: "check list of pods",
+		},
+		{
+			name: "check list with field selector",
+		},
+		{
+			name: "check optional missing resource",
+		},
 	}
 
 	const testNamespace = "kuttl-ephemeral-xyz"
@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
-	"github.com/google/cel-go/cel"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,6 +18,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/jsonpath"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kudobuilder/kuttl/internal/env"
@@ -39,11 +42,24 @@ type Step struct {
 	Dir           string
 	TestRunLabels labels.Set
 	TemplateEnv   template.Env
+	// Templated is set if any of the files of the step is a template, whose expansion may depend on the cluster
+	// and the previous steps.
+	Templated bool
+	// Variables of the test case, shared by all of its steps.
+	Variables testutils.Variables
 
 	Step   *harness.TestStep
 	Assert *harness.TestAssert
+	// Collectors of the test case and test suite, run after those of the TestAssert. They must be validated.
+	DefaultCollectors []*harness.TestCollector
+	// Paths of the files written by the collectors to the artifacts directory when the step was run.
+	Artifacts []string
 
-	Programs map[string]cel.Program
+	Programs map[string]*expressions.Program
+	// CEL programs of the bindings of the TestStep, keyed by binding name.
+	BindingPrograms map[string]*expressions.Program
+	// Expected objects of Asserts and Errors which hold object expressions, compiled when the step is loaded.
+	ObjectExpressions map[runtime.Object]ExpectedObject
 
 	Asserts []client.Object
 	Apply   []client.Object
@@ -57,6 +73,8 @@ type Step struct {
 
 	Client          func(forceNew bool) (client.Client, error)
 	DiscoveryClient func() (discovery.DiscoveryInterface, error)
+	// Config returns the REST config of the step's cluster, for clients other than Client.
+	Config func() (*rest.Config, error)
 
 	Logger testutils.Logger
 }
@@ -162,7 +180,9 @@ func (s *Step) DeleteExisting(namespace string) error {
 		if k8serrors.IsNotFound(err) {
 			action = "already gone"
 		}
-		s.Logger.Log(kubernetes.ResourceID(del), action)
+		if !s.suppresses(testutils.LogResources) {
+			s.Logger.Log(kubernetes.ResourceID(del), action)
+		}
 	}
 
 	return kubernetes.WaitForDelete(cl, toDelete, time.Duration(s.GetTimeout())*time.Second)
@@ -203,7 +223,12 @@ func (s *Step) Create(test *testing.T, namespace string) []error {
 				obj := obj
 				test.Cleanup(func() {
 					if err := cl.Delete(context.TODO(), obj); err != nil && !k8serrors.IsNotFound(err) {
-						test.Error(err)
+						if s.Logger == nil {
+							test.Error(err)
+							return
+						}
+						s.Logger.Errorf("%v", err)
+						test.Fail()
 					}
 				})
 			}
@@ -211,7 +236,9 @@ func (s *Step) Create(test *testing.T, namespace string) []error {
 			if updated {
 				action = "updated"
 			}
-			s.Logger.Log(kubernetes.ResourceID(obj), action)
+			if !s.suppresses(testutils.LogResources) {
+				s.Logger.Log(kubernetes.ResourceID(obj), action)
+			}
 		}
 	}
 
@@ -247,6 +274,16 @@ func list(cl client.Client, gvk schema.GroupVersionKind, namespace string, label
 	return list.Items, nil
 }
 
+// DiffError is the diff between the expected and actual resource of a failed assertion.
+// It is part of the errors of the assertion, but its logging can be suppressed with the LogDiffs category.
+type DiffError struct {
+	Diff string
+}
+
+func (e *DiffError) Error() string {
+	return e.Diff
+}
+
 // CheckResource checks if the expected resource's state in Kubernetes is correct.
 func (s *Step) CheckResource(expected runtime.Object, namespace string) []error {
 	cl, err := s.Client(false)
@@ -295,7 +332,7 @@ func (s *Step) CheckResource(expected runtime.Object, namespace string) []error
 		}
 		actuals = append(actuals, matches...)
 	}
-	expectedObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected)
+	expectedObj, objectExpressions, err := s.expectedContent(expected)
 	if err != nil {
 		return append(testErrors, err)
 	}
@@ -307,12 +344,18 @@ func (s *Step) CheckResource(expected runtime.Object, namespace string) []error
 			diff, diffErr := kubernetes.PrettyDiff(
 				&unstructured.Unstructured{Object: expectedObj}, &actual)
 			if diffErr == nil {
-				tmpTestErrors = append(tmpTestErrors, errors.New(diff))
+				tmpTestErrors = append(tmpTestErrors, &DiffError{Diff: diff})
 			} else {
 				tmpTestErrors = append(tmpTestErrors, diffErr)
 			}
 
 			tmpTestErrors = append(tmpTestErrors, fmt.Errorf("resource %s: %s", kubernetes.ResourceID(expected), err))
+		} else {
+			for _, objectExpression := range objectExpressions {
+				if err := objectExpression.Evaluate(actual.UnstructuredContent(), s.vars()); err != nil {
+					tmpTestErrors = append(tmpTestErrors, fmt.Errorf("resource %s: %w", kubernetes.ResourceID(&actual), err))
+				}
+			}
 		}
 
 		if len(tmpTestErrors) == 0 {
@@ -373,14 +416,14 @@ func (s *Step) CheckResourceAbsent(expected runtime.Object, namespace string) er
 		}
 	}
 
-	expectedObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected)
+	expectedObj, objectExpressions, err := s.expectedContent(expected)
 	if err != nil {
 		return err
 	}
 
 	var unexpectedObjects []unstructured.Unstructured
 	for _, actual := range actuals {
-		if err := testutils.IsSubset(expectedObj, actual.UnstructuredContent()); err == nil {
+		if err := testutils.IsSubset(expectedObj, actual.UnstructuredContent()); err == nil && s.matchesExpressions(objectExpressions, actual) {
 			unexpectedObjects = append(unexpectedObjects, actual)
 		}
 	}
@@ -394,16 +437,169 @@ func (s *Step) CheckResourceAbsent(expected runtime.Object, namespace string) er
 	return fmt.Errorf("resource %s %s (and %d other resources) matched error assertion", unexpectedObjects[0].GroupVersionKind(), unexpectedObjects[0].GetName(), len(unexpectedObjects)-1)
 }
 
+// ExpectedObject is the content of an expected object without the object expressions it contains,
+// along with these expressions, compiled.
+type ExpectedObject struct {
+	Content     map[string]any
+	Expressions []expressions.ObjectExpression
+}
+
+// expectedContent is like the expectedContent function, but only compiles the object expressions of each object
+// once, recording them in ObjectExpressions.
+func (s *Step) expectedContent(expected runtime.Object) (map[string]any, []expressions.ObjectExpression, error) {
+	if e, ok := s.ObjectExpressions[expected]; ok {
+		return e.Content, e.Expressions, nil
+	}
+	content, objectExpressions, err := expectedContent(expected)
+	if err != nil || len(objectExpressions) == 0 {
+		return content, objectExpressions, err
+	}
+	if s.ObjectExpressions == nil {
+		s.ObjectExpressions = map[runtime.Object]ExpectedObject{}
+	}
+	s.ObjectExpressions[expected] = ExpectedObject{Content: content, Expressions: objectExpressions}
+	return content, objectExpressions, nil
+}
+
+// expectedContent returns the content of an expected object, without the object expressions it contains,
+// and the compiled object expressions.
+func expectedContent(expected runtime.Object) (map[string]any, []expressions.ObjectExpression, error) {
+	expectedObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected)
+	if err != nil {
+		return nil, nil, err
+	}
+	expectedObj, objectExpressions, err := expressions.ExtractObjectExpressions(expectedObj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resource %s: %w", kubernetes.ResourceID(expected), err)
+	}
+	return expectedObj, objectExpressions, nil
+}
+
+// matchesExpressions returns true if all the object expressions evaluate to true for actual.
+func (s *Step) matchesExpressions(objectExpressions []expressions.ObjectExpression, actual unstructured.Unstructured) bool {
+	for _, objectExpression := range objectExpressions {
+		if err := objectExpression.Evaluate(actual.UnstructuredContent(), s.vars()); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 // CheckAssertCommands Runs the commands provided in `commands` and check if have been run successfully.
 // the errors returned can be a failure of executing the command or the failure of the command executed.
 func (s *Step) CheckAssertCommands(ctx context.Context, namespace string, commands []harness.TestAssertCommand, timeout int) []error {
 	testErrors := []error{}
-	if _, err := testutils.RunAssertCommands(ctx, s.Logger, namespace, commands, s.Dir, timeout, s.Kubeconfig); err != nil {
+	if _, err := testutils.RunAssertCommands(ctx, s.Logger, namespace, commands, s.Dir, timeout, s.Kubeconfig, s.Variables); err != nil {
 		testErrors = append(testErrors, err)
 	}
 	return testErrors
 }
 
+// loadBindings validates the bindings of the TestStep and prepares their CEL programs.
+func (s *Step) loadBindings() error {
+	for _, binding := range s.Step.Bindings {
+		if err := binding.Validate(); err != nil {
+			return fmt.Errorf("invalid binding %q: %w", binding.Name, err)
+		}
+		if err := testutils.ValidateVariableName(binding.Name); err != nil {
+			return fmt.Errorf("invalid binding %q: %w", binding.Name, err)
+		}
+		if binding.JSONPath != "" {
+			if err := jsonpath.New(binding.Name).Parse(binding.JSONPath); err != nil {
+				return fmt.Errorf("invalid binding %q: parsing JSONPath %q: %w", binding.Name, binding.JSONPath, err)
+			}
+		}
+	}
+
+	var err error
+	s.BindingPrograms, err = expressions.LoadBindingPrograms(s.Step.Bindings)
+	return err
+}
+
+// EvaluateBindings stores the values of the bindings of the TestStep in the test case variables.
+func (s *Step) EvaluateBindings(namespace string) error {
+	if s.Step == nil || len(s.Step.Bindings) == 0 {
+		return nil
+	}
+
+	cl, err := s.Client(false)
+	if err != nil {
+		return err
+	}
+	if s.Variables == nil {
+		s.Variables = testutils.Variables{}
+	}
+
+	for _, binding := range s.Step.Bindings {
+		resourceRef := binding.ResourceRef
+		if resourceRef.Namespace == "" {
+			resourceRef.Namespace = namespace
+		}
+		referenced, err := getReferencedResource(context.TODO(), cl, resourceRef)
+		if err != nil {
+			return fmt.Errorf("binding %q: %w", binding.Name, err)
+		}
+
+		var value any
+		switch {
+		case binding.CELExpression != "":
+			variables := map[string]interface{}{resourceRef.Ref: referenced}
+			if _, ok := variables[expressions.VarsVariable]; !ok {
+				variables[expressions.VarsVariable] = s.vars()
+			}
+			value, err = expressions.EvaluateBinding(s.BindingPrograms[binding.Name], variables)
+		case referenced != nil:
+			value, err = testutils.EvalJSONPath(binding.JSONPath, referenced)
+		default:
+			// A missing optional object binds null.
+		}
+		if err != nil {
+			return fmt.Errorf("binding %q: %w", binding.Name, err)
+		}
+
+		s.Logger.Debugf("binding %s set from %s", binding.Name, resourceRef.String())
+		s.Variables[binding.Name] = value
+	}
+	return nil
+}
+
+// getReferencedResource returns the content of the object referenced by resourceRef,
+// or the list of the contents of the referenced objects if it is a list reference.
+// A missing object is returned as nil if the reference is optional.
+func getReferencedResource(ctx context.Context, cl client.Client, resourceRef harness.TestResourceRef) (any, error) {
+	if !resourceRef.List {
+		namespacedName, referencedResource := resourceRef.BuildResourceReference()
+		if err := cl.Get(ctx, namespacedName, referencedResource); err != nil {
+			if resourceRef.Optional && k8serrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get referenced resource '%v': %w", namespacedName, err)
+		}
+		return referencedResource.Object, nil
+	}
+
+	list, labelSelector, fieldSelector, err := resourceRef.BuildResourceListReference()
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.ListOption{client.InNamespace(resourceRef.Namespace)}
+	if !labelSelector.Empty() {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: labelSelector})
+	}
+	if !fieldSelector.Empty() {
+		opts = append(opts, client.MatchingFieldsSelector{Selector: fieldSelector})
+	}
+	if err := cl.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list referenced resources '%v': %w", resourceRef.String(), err)
+	}
+
+	items := make([]any, 0, len(list.Items))
+	for _, item := range list.Items {
+		items = append(items, item.Object)
+	}
+	return items, nil
+}
+
 // CheckAssertExpressions validates assertion expressions against the current cluster state.
 func (s *Step) CheckAssertExpressions(namespace string) []error {
 	client, err := s.Client(false)
@@ -416,17 +612,33 @@ func (s *Step) CheckAssertExpressions(namespace string) []error {
 		if resourceRef.Namespace == "" {
 			resourceRef.Namespace = namespace
 		}
-		namespacedName, referencedResource := resourceRef.BuildResourceReference()
-		if err := client.Get(context.TODO(), namespacedName, referencedResource); err != nil {
-			return []error{fmt.Errorf("failed to get referenced resource '%v': %w", namespacedName, err)}
+		referenced, err := getReferencedResource(context.TODO(), client, resourceRef)
+		if err != nil {
+			return []error{err}
 		}
 
-		variables[resourceRef.Ref] = referencedResource.Object
+		variables[resourceRef.Ref] = referenced
+	}
+
+	if _, ok := variables[expressions.VarsVariable]; !ok {
+		variables[expressions.VarsVariable] = s.vars()
 	}
 
 	return expressions.RunAssertExpressions(s.Programs, variables, s.Assert.AssertAny, s.Assert.AssertAll)
 }
 
+// vars returns the template variables merged with the test case variables captured so far.
+func (s *Step) vars() map[string]any {
+	vars := make(map[string]any, len(s.TemplateEnv.Vars)+len(s.Variables))
+	for k, v := range s.TemplateEnv.Vars {
+		vars[k] = v
+	}
+	for k, v := range s.Variables {
+		vars[k] = v
+	}
+	return vars
+}
+
 // Check checks if the resources defined in Asserts and Errors are in the correct state.
 func (s *Step) Check(namespace string, timeout int) []error {
 	testErrors := []error{}
@@ -438,6 +650,9 @@ func (s *Step) Check(namespace string, timeout int) []error {
 	if s.Assert != nil {
 		testErrors = append(testErrors, s.CheckAssertCommands(context.TODO(), namespace, s.Assert.Commands, timeout)...)
 		testErrors = append(testErrors, s.CheckAssertExpressions(namespace)...)
+		testErrors = append(testErrors, s.CheckAssertExec(context.TODO(), namespace, timeout)...)
+		testErrors = append(testErrors, s.CheckAssertLogs(context.TODO(), namespace)...)
+		testErrors = append(testErrors, s.CheckAssertEvents(context.TODO(), namespace)...)
 	}
 
 	for _, expected := range s.Errors {
@@ -454,12 +669,25 @@ func (s *Step) Check(namespace string, timeout int) []error {
 // 2. Run step commands.
 // 3. Apply all desired objects to Kubernetes.
 // 4. Stop if the above fails.
-// 5. Check assertions in a loop until they all pass or step times out.
-// 6. On success, return.
-// 7. On failure, run collector commands, if any.
+// 5. Wait for the states declared in the TestStep, if any. Stop if this fails.
+// 6. Execute the commands declared in the TestStep in pods, if any. Stop if this fails.
+// 7. Check assertions in a loop until they all pass or step times out.
+// 8. On success, evaluate bindings.
+// 9. Run the collectors of the TestAssert, test case and test suite which apply to the outcome of the step, if any.
 func (s *Step) Run(test *testing.T, namespace string) []error {
 	s.Logger.Log("starting test step", s.String())
 
+	testErrors := s.run(test, namespace)
+	if len(testErrors) == 0 {
+		s.Logger.Log("test step completed", s.String())
+	} else {
+		s.Logger.Log("test step failed", s.String())
+	}
+	s.Collect(namespace, len(testErrors) > 0)
+	return testErrors
+}
+
+func (s *Step) run(test *testing.T, namespace string) []error {
 	if err := s.DeleteExisting(namespace); err != nil {
 		return []error{err}
 	}
@@ -469,11 +697,11 @@ func (s *Step) Run(test *testing.T, namespace string) []error {
 	if s.Step != nil {
 		for _, command := range s.Step.Commands {
 			if command.Background {
-				s.Logger.Log("background commands are not allowed for steps and will be run in foreground")
+				s.Logger.Warnf("background commands are not allowed for steps and will be run in foreground")
 				command.Background = false
 			}
 		}
-		if _, err := testutils.RunCommands(context.TODO(), s.Logger, namespace, s.Step.Commands, s.Dir, s.Timeout, s.Kubeconfig); err != nil {
+		if _, err := testutils.RunCommands(context.TODO(), s.Logger, namespace, s.Step.Commands, s.Dir, s.Timeout, s.Kubeconfig, s.Variables); err != nil {
 			testErrors = append(testErrors, err)
 		}
 	}
@@ -484,46 +712,46 @@ func (s *Step) Run(test *testing.T, namespace string) []error {
 		return testErrors
 	}
 
-	timeoutF := float64(s.GetTimeout())
-	start := time.Now()
+	// The waits, the execs and the assertions share the timeout of the step.
+	deadline := time.Now().Add(time.Duration(s.GetTimeout()) * time.Second)
+	ctx, cancel := context.WithDeadline(context.TODO(), deadline)
+	defer cancel()
+
+	if err := s.Wait(ctx, namespace); err != nil {
+		return []error{err}
+	}
+
+	if err := s.Exec(ctx, namespace); err != nil {
+		return []error{err}
+	}
 
-	for elapsed := 0.0; elapsed < timeoutF; elapsed = time.Since(start).Seconds() {
-		testErrors = s.Check(namespace, int(timeoutF-elapsed))
+	// The assertions are checked at least once, even if the waits and execs used up the timeout.
+	for {
+		testErrors = s.Check(namespace, remainingSeconds(deadline))
 
 		if len(testErrors) == 0 {
 			break
 		}
-		if hasTimeoutErr(testErrors) {
+		if hasTimeoutErr(testErrors) || time.Until(deadline) <= 0 {
 			break
 		}
 		time.Sleep(time.Second)
 	}
 
-	// all is good
 	if len(testErrors) == 0 {
-		s.Logger.Log("test step completed", s.String())
-		return testErrors
-	}
-	// test failure processing
-	s.Logger.Log("test step failed", s.String())
-	if s.Assert == nil {
-		return testErrors
-	}
-	for _, collector := range s.Assert.Collectors {
-		s.Logger.Logf("collecting log output for %s", collector.String())
-		if collector.Command() == nil {
-			s.Logger.Log("skipping invalid assertion collector")
-			continue
-		}
-		_, err := testutils.RunCommand(context.TODO(), namespace, *collector.Command(), s.Dir, s.Logger, s.Logger, s.Logger, s.Timeout, s.Kubeconfig)
-		if err != nil {
-			s.Logger.Log("post assert collector failure: %s", err)
+		if err := s.EvaluateBindings(namespace); err != nil {
+			testErrors = append(testErrors, err)
 		}
 	}
-	s.Logger.Flush()
 	return testErrors
 }
 
+// suppresses reports whether the logs of category are suppressed. Steps created without a logger, e.g. by the
+// assert command, suppress nothing.
+func (s *Step) suppresses(category string) bool {
+	return s.Logger != nil && s.Logger.Suppresses(category)
+}
+
 // String implements the string interface, returning the name of the test step.
 func (s *Step) String() string {
 	return fmt.Sprintf("%d-%s", s.Index, s.Name)
@@ -554,6 +782,21 @@ func (s *Step) LoadYAML(f kfile.Info) error {
 			if err != nil {
 				return fmt.Errorf("failed to prepare expression evaluation: %w", err)
 			}
+			for i := range s.Assert.Exec {
+				if err := s.Assert.Exec[i].Validate(); err != nil {
+					return fmt.Errorf("invalid exec assertion %d: %w", i, err)
+				}
+			}
+			for i := range s.Assert.Logs {
+				if err := s.Assert.Logs[i].Validate(); err != nil {
+					return fmt.Errorf("invalid logs assertion %d: %w", i, err)
+				}
+			}
+			for i := range s.Assert.Events {
+				if err := s.Assert.Events[i].Validate(); err != nil {
+					return fmt.Errorf("invalid events assertion %d: %w", i, err)
+				}
+			}
 		} else {
 			asserts = append(asserts, obj)
 		}
@@ -587,6 +830,20 @@ func (s *Step) LoadYAML(f kfile.Info) error {
 			default:
 				return fmt.Errorf("attribute 'kubeconfigLoading' has invalid value %q", s.Step.KubeconfigLoading)
 			}
+
+			if err := s.loadBindings(); err != nil {
+				return err
+			}
+			for i := range s.Step.Wait {
+				if err := s.Step.Wait[i].Validate(); err != nil {
+					return fmt.Errorf("invalid wait %d: %w", i, err)
+				}
+			}
+			for i := range s.Step.Exec {
+				if err := s.Step.Exec[i].Validate(); err != nil {
+					return fmt.Errorf("invalid exec %d: %w", i, err)
+				}
+			}
 		} else {
 			applies = append(applies, obj)
 		}
@@ -597,7 +854,7 @@ func (s *Step) LoadYAML(f kfile.Info) error {
 		// process configured step applies
 		for _, applyPath := range s.Step.Apply {
 			exApply := env.Expand(applyPath)
-			apply, err := ObjectsFromPath(exApply, s.Dir)
+			apply, err := s.objectsFromPath(exApply)
 			if err != nil {
 				return fmt.Errorf("step %q apply path %s: %w", s.Name, exApply, err)
 			}
@@ -606,7 +863,7 @@ func (s *Step) LoadYAML(f kfile.Info) error {
 		// process configured step asserts
 		for _, assertPath := range s.Step.Assert {
 			exAssert := env.Expand(assertPath)
-			assert, err := ObjectsFromPath(exAssert, s.Dir)
+			assert, err := s.objectsFromPath(exAssert)
 			if err != nil {
 				return fmt.Errorf("step %q assert path %s: %w", s.Name, exAssert, err)
 			}
@@ -615,7 +872,7 @@ func (s *Step) LoadYAML(f kfile.Info) error {
 		// process configured errors
 		for _, errorPath := range s.Step.Error {
 			exError := env.Expand(errorPath)
-			errObjs, err := ObjectsFromPath(exError, s.Dir)
+			errObjs, err := s.objectsFromPath(exError)
 			if err != nil {
 				return fmt.Errorf("step %q error path %s: %w", s.Name, exError, err)
 			}
@@ -623,6 +880,13 @@ func (s *Step) LoadYAML(f kfile.Info) error {
 		}
 	}
 
+	// The object expressions are compiled once, so that invalid ones fail the loading of the step.
+	for _, obj := range slices.Concat(asserts, s.Errors) {
+		if _, _, err := s.expectedContent(obj); err != nil {
+			return err
+		}
+	}
+
 	s.Apply = applies
 	s.Asserts = asserts
 	return nil
@@ -662,8 +926,22 @@ func (s *Step) loadOrSkipFile(info kfile.Info) (bool, []client.Object, error) {
 }
 
 func (s *Step) loadYAML(info kfile.Info) ([]client.Object, error) {
+	s.Templated = s.Templated || info.IsTemplate
+	return loadFile(info, s.TemplateEnv)
+}
+
+// objectsFromPath returns the objects of the files or url at path, relative to the directory of the step,
+// expanding templates with the template environment of the step.
+func (s *Step) objectsFromPath(path string) ([]client.Object, error) {
+	objects, templated, err := objectsFromPath(path, s.Dir, &s.TemplateEnv)
+	s.Templated = s.Templated || templated
+	return objects, err
+}
+
+// loadFile loads the objects from a file, expanding it with templateEnv first if it is a template.
+func loadFile(info kfile.Info, templateEnv template.Env) ([]client.Object, error) {
 	if info.IsTemplate {
-		expanded, err := template.LoadAndExpand(info.FullName, s.TemplateEnv)
+		expanded, err := template.LoadAndExpand(info.FullName, templateEnv)
 		if err != nil {
 			return nil, err
 		}
@@ -693,38 +971,62 @@ func (s *Step) populateObjectsByType(f kfile.Info, objects []client.Object) erro
 }
 
 // Setup prepares the step by configuring its logger and client provider methods.
-func (s *Step) Setup(caseLogger testutils.Logger, defaultClientFunc func(forceNew bool) (client.Client, error), defaultDiscoveryClientFunc func() (discovery.DiscoveryInterface, error)) {
+func (s *Step) Setup(caseLogger testutils.Logger, defaultClientFunc func(forceNew bool) (client.Client, error), defaultDiscoveryClientFunc func() (discovery.DiscoveryInterface, error),
+	defaultConfigFunc func() (*rest.Config, error)) {
 	s.Logger = caseLogger.WithPrefix(s.String())
 	if s.Kubeconfig != "" {
 		s.Client = kubernetes.NewClientFunc(s.Kubeconfig, s.Context)
 		s.DiscoveryClient = kubernetes.NewDiscoveryClientFunc(s.Kubeconfig, s.Context)
+		s.Config = func() (*rest.Config, error) {
+			return kubernetes.BuildConfigWithContext(s.Kubeconfig, s.Context)
+		}
 	} else {
 		s.Client = defaultClientFunc
 		s.DiscoveryClient = defaultDiscoveryClientFunc
+		s.Config = defaultConfigFunc
 	}
 }
 
 // ObjectsFromPath returns an array of runtime.Objects for files / urls provided.
-func ObjectsFromPath(path, dir string) ([]client.Object, error) {
+// If templateEnv is not nil, files with names ending in .gotmpl.yaml are expanded with it before parsing.
+func ObjectsFromPath(path, dir string, templateEnv *template.Env) ([]client.Object, error) {
+	objects, _, err := objectsFromPath(path, dir, templateEnv)
+	return objects, err
+}
+
+// objectsFromPath implements ObjectsFromPath, also reporting whether any of the files was expanded as a template.
+func objectsFromPath(path, dir string, templateEnv *template.Env) ([]client.Object, bool, error) {
 	if http.IsURL(path) {
 		apply, err := http.ToObjects(path)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		return apply, nil
+		return apply, false, nil
 	}
 
 	// it's a directory or file
 	cPath := cleanPath(path, dir)
 	paths, err := kfile.FromPath(cPath, "*.yaml")
 	if err != nil {
-		return nil, fmt.Errorf("failed to find YAML files in %s: %w", cPath, err)
+		return nil, false, fmt.Errorf("failed to find YAML files in %s: %w", cPath, err)
 	}
-	apply, err := kfile.ToObjects(paths)
-	if err != nil {
-		return nil, err
+	if templateEnv == nil {
+		objects, err := kfile.ToObjects(paths)
+		return objects, false, err
+	}
+
+	apply := []client.Object{}
+	templated := false
+	for _, path := range paths {
+		info := kfile.Parse(path)
+		templated = templated || info.IsTemplate
+		objs, err := loadFile(info, *templateEnv)
+		if err != nil {
+			return nil, false, fmt.Errorf("file %q load yaml error: %w", path, err)
+		}
+		apply = append(apply, objs...)
 	}
-	return apply, nil
+	return apply, templated, nil
 }
 
 // cleanPath returns either the abs path or the joined path.
@@ -735,6 +1037,11 @@ func cleanPath(path, dir string) string {
 	return filepath.Join(dir, path)
 }
 
+// remainingSeconds returns the number of seconds left until deadline, rounded up, and at least 1.
+func remainingSeconds(deadline time.Time) int {
+	return max(int(math.Ceil(time.Until(deadline).Seconds())), 1)
+}
+
 func hasTimeoutErr(err []error) bool {
 	for i := range err {
 		if errors.Is(err[i], context.DeadlineExceeded) {
@@ -1,6 +1,14 @@
 package step
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,17 +17,22 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/kudobuilder/kuttl/internal/expressions"
 	"github.com/kudobuilder/kuttl/internal/kubernetes"
 	k8sfake "github.com/kudobuilder/kuttl/internal/kubernetes/fake"
+	"github.com/kudobuilder/kuttl/internal/template"
 	testutils "github.com/kudobuilder/kuttl/internal/utils"
 	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
 )
@@ -208,6 +221,30 @@ func TestCheckResource(t *testing.T) {
 			expected:    kubernetes.NewPod("hello", ""),
 			shouldError: true,
 		},
+		{
+			testName: "resource matches expression",
+			actual: []runtime.Object{
+				kubernetes.NewV1Pod("pod1", "", "invalid"),
+				kubernetes.NewV1Pod("pod2", "", "valid"),
+			},
+			expected: kubernetes.WithSpec(t, kubernetes.NewPod("", ""), map[string]interface{}{
+				"($cel)": "self.serviceAccountName == 'valid'",
+			}),
+		},
+		{
+			testName: "resource matches object expression",
+			actual:   []runtime.Object{kubernetes.NewPod("hello", "")},
+			expected: withObjectExpression(kubernetes.NewPod("hello", ""), []interface{}{
+				"object.metadata.name.startsWith('hel')",
+				"self == object",
+			}),
+		},
+		{
+			testName:    "resource fails expression",
+			actual:      []runtime.Object{kubernetes.NewV1Pod("hello", "", "invalid")},
+			expected:    kubernetes.WithSpec(t, kubernetes.NewPod("hello", ""), map[string]interface{}{"($cel)": "self.serviceAccountName == 'valid'"}),
+			shouldError: true,
+		},
 	} {
 		t.Run(test.testName, func(t *testing.T) {
 			fakeDiscovery := k8sfake.DiscoveryClient()
@@ -236,6 +273,67 @@ func TestCheckResource(t *testing.T) {
 	}
 }
 
+func TestCheckResourceSuppressDiffs(t *testing.T) {
+	fakeDiscovery := k8sfake.DiscoveryClient()
+	actual := kubernetes.NewPod("hello", testNamespace)
+	expected := kubernetes.WithSpec(t, kubernetes.NewPod("hello", ""), map[string]interface{}{"invalid": "key"})
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(actual).Build()
+
+	// The diff is part of the errors even if its logging is suppressed.
+	for _, suppressions := range [][]string{nil, {"diffs"}} {
+		step := Step{
+			Logger:          testutils.NewTestLogger(t, "", testutils.WithSuppressions(suppressions)),
+			Client:          func(bool) (client.Client, error) { return cl, nil },
+			DiscoveryClient: func() (discovery.DiscoveryInterface, error) { return fakeDiscovery, nil },
+		}
+
+		errs := step.CheckResource(expected, testNamespace)
+		require.Len(t, errs, 2, suppressions)
+		var diffErr *DiffError
+		require.ErrorAs(t, errs[0], &diffErr, suppressions)
+		assert.Contains(t, diffErr.Diff, "-  invalid: key", suppressions)
+		assert.ErrorContains(t, errs[1], ".spec.invalid: key is missing from map")
+	}
+}
+
+func TestLoadYAMLCompilesObjectExpressions(t *testing.T) {
+	dir := t.TempDir()
+	assertFile := filepath.Join(dir, "00-assert.yaml")
+	require.NoError(t, os.WriteFile(assertFile, []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: hello
+spec:
+  ($cel): self.restartPolicy == 'Never'
+`), 0600))
+
+	step := &Step{Dir: dir, Logger: testutils.NewTestLogger(t, "")}
+	require.NoError(t, step.LoadYAML(kfile.Parse(assertFile)))
+	require.Len(t, step.Asserts, 1)
+	expected, ok := step.ObjectExpressions[step.Asserts[0]]
+	require.True(t, ok, "the object expressions are compiled when the step is loaded")
+	require.Len(t, expected.Expressions, 1)
+	assert.NotContains(t, expected.Content["spec"], "($cel)")
+
+	fakeDiscovery := k8sfake.DiscoveryClient()
+	actual := kubernetes.WithSpec(t, kubernetes.NewPod("hello", testNamespace), map[string]any{"restartPolicy": "Never"})
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(actual).Build()
+	step.Client = func(bool) (client.Client, error) { return cl, nil }
+	step.DiscoveryClient = func() (discovery.DiscoveryInterface, error) { return fakeDiscovery, nil }
+	assert.Empty(t, step.CheckResource(step.Asserts[0], testNamespace))
+	assert.Same(t, &expected.Expressions[0], &step.ObjectExpressions[step.Asserts[0]].Expressions[0], "the object expressions are not compiled again")
+
+	require.NoError(t, os.WriteFile(assertFile, []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: hello
+spec:
+  ($cel): self.restartPolicy ==
+`), 0600))
+	step = &Step{Dir: dir, Logger: testutils.NewTestLogger(t, "")}
+	assert.ErrorContains(t, step.LoadYAML(kfile.Parse(assertFile)), "failed to build CEL program")
+}
+
 func TestCheckResourceAbsent(t *testing.T) {
 	for _, test := range []struct {
 		name        string
@@ -281,6 +379,21 @@ func TestCheckResourceAbsent(t *testing.T) {
 			actual:   []runtime.Object{kubernetes.NewPod("other", "")},
 			expected: kubernetes.NewPod("hello", ""),
 		},
+		{
+			name: "resource matches expression",
+			actual: []runtime.Object{
+				kubernetes.NewV1Pod("pod1", "", "val1"),
+				kubernetes.NewV1Pod("pod2", "", "val2"),
+			},
+			expected:    withObjectExpression(kubernetes.NewPod("", ""), "object.spec.serviceAccountName.endsWith('2')"),
+			shouldError: true,
+			expectedErr: "resource /v1, Kind=Pod pod2 matched error assertion",
+		},
+		{
+			name:     "resource fails expression",
+			actual:   []runtime.Object{kubernetes.NewV1Pod("hello", "", "val1")},
+			expected: withObjectExpression(kubernetes.NewPod("hello", ""), "object.spec.serviceAccountName == 'val2'"),
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			fakeDiscovery := k8sfake.DiscoveryClient()
@@ -312,6 +425,12 @@ func TestCheckResourceAbsent(t *testing.T) {
 	}
 }
 
+func withObjectExpression(obj *unstructured.Unstructured, exprs interface{}) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.Object["($cel)"] = exprs
+	return obj
+}
+
 func TestRun(t *testing.T) {
 	for _, test := range []struct {
 		testName     string
@@ -424,3 +543,497 @@ func TestPopulateObjectsByFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyTemplateExpansion(t *testing.T) {
+	step := Step{
+		Dir: "test_data/apply_template/",
+		TemplateEnv: template.Env{
+			CaseName: "case",
+			StepName: "step",
+			Vars:     map[string]any{"suffix": "foo"},
+		},
+	}
+	require.NoError(t, step.LoadYAML(kfile.Parse("test_data/apply_template/00-step.yaml")))
+	require.Len(t, step.Apply, 2)
+	assert.Equal(t, "plain", step.Apply[0].GetName())
+	assert.Equal(t, "case-step-foo", step.Apply[1].GetName())
+}
+
+func TestEvaluateBindings(t *testing.T) {
+	pod := kubernetes.NewPod("hello", testNamespace)
+	pod.SetUID("1234")
+	pod.SetLabels(map[string]string{"app": "hello"})
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+
+	podRef := harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "hello", Ref: "pod"}
+	step := Step{
+		Step: &harness.TestStep{
+			Bindings: []harness.Binding{
+				{Name: "uid", ResourceRef: harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "hello"}, JSONPath: "{.metadata.uid}"},
+				{Name: "labels", ResourceRef: podRef, CELExpression: "pod.metadata.labels"},
+				{Name: "greeting", ResourceRef: podRef, CELExpression: "vars.prefix + '-' + pod.metadata.name"},
+			},
+		},
+		TemplateEnv: template.Env{Vars: map[string]any{"prefix": "hi"}},
+		Variables:   testutils.Variables{},
+		Client:      func(bool) (client.Client, error) { return cl, nil },
+		Logger:      testutils.NewTestLogger(t, ""),
+	}
+	require.NoError(t, step.loadBindings())
+	require.NoError(t, step.EvaluateBindings(testNamespace))
+	assert.Equal(t, testutils.Variables{
+		"uid":      "1234",
+		"labels":   map[string]any{"app": "hello"},
+		"greeting": "hi-hello",
+	}, step.Variables)
+
+	step.Step.Bindings = []harness.Binding{{Name: "missing", ResourceRef: harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "missing"}, JSONPath: "{.metadata.uid}"}}
+	require.NoError(t, step.loadBindings())
+	assert.ErrorContains(t, step.EvaluateBindings(testNamespace), `binding "missing": failed to get referenced resource`)
+
+	optionalRef := harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "missing", Ref: "pod", Optional: true}
+	step.Step.Bindings = []harness.Binding{
+		{Name: "missing", ResourceRef: optionalRef, JSONPath: "{.metadata.uid}"},
+		{Name: "phase", ResourceRef: optionalRef, CELExpression: "pod == null ? 'Absent' : pod.status.phase"},
+	}
+	require.NoError(t, step.loadBindings())
+	require.NoError(t, step.EvaluateBindings(testNamespace))
+	assert.Nil(t, step.Variables["missing"])
+	assert.Contains(t, step.Variables, "missing")
+	assert.Equal(t, "Absent", step.Variables["phase"])
+
+	// A resource referenced as vars shadows the variables.
+	step.Step.Bindings = []harness.Binding{
+		{Name: "shadowed", ResourceRef: harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "hello", Ref: "vars"}, CELExpression: "vars.metadata.name"},
+	}
+	require.NoError(t, step.loadBindings())
+	require.NoError(t, step.EvaluateBindings(testNamespace))
+	assert.Equal(t, "hello", step.Variables["shadowed"])
+}
+
+func TestCheckAssertExpressionsOptional(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(kubernetes.NewPod("hello", testNamespace)).Build()
+	testAssert := &harness.TestAssert{
+		ResourceRefs: []harness.TestResourceRef{
+			{APIVersion: "v1", Kind: "Pod", Name: "hello", Ref: "pod", Optional: true},
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "missing", Ref: "config", Optional: true},
+		},
+		AssertAll: []*harness.Assertion{
+			{CELExpression: "pod != null && pod.metadata.name == 'hello'"},
+			{CELExpression: "config == null || 'x' in config.data"},
+		},
+	}
+	programs, err := expressions.LoadPrograms(testAssert)
+	require.NoError(t, err)
+
+	step := Step{
+		Assert:   testAssert,
+		Programs: programs,
+		Client:   func(bool) (client.Client, error) { return cl, nil },
+	}
+	assert.Empty(t, step.CheckAssertExpressions(testNamespace))
+
+	testAssert.ResourceRefs[1].Optional = false
+	errs := step.CheckAssertExpressions(testNamespace)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "failed to get referenced resource")
+}
+
+func TestLoadBindingsInvalid(t *testing.T) {
+	podRef := harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "hello"}
+	for name, binding := range map[string]harness.Binding{
+		"no value source":   {Name: "foo", ResourceRef: podRef},
+		"both sources":      {Name: "foo", ResourceRef: podRef, JSONPath: "{.metadata.uid}", CELExpression: "true"},
+		"invalid name":      {Name: "foo-bar", ResourceRef: podRef, JSONPath: "{.metadata.uid}"},
+		"invalid JSONPath":  {Name: "foo", ResourceRef: podRef, JSONPath: "{.metadata"},
+		"CEL without ref":   {Name: "foo", ResourceRef: podRef, CELExpression: "true"},
+		"invalid CEL":       {Name: "foo", ResourceRef: harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Name: "hello", Ref: "pod"}, CELExpression: "pod."},
+		"missing reference": {Name: "foo", ResourceRef: harness.TestResourceRef{APIVersion: "v1", Name: "hello"}, JSONPath: "{.metadata.uid}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			step := Step{Step: &harness.TestStep{Bindings: []harness.Binding{binding}}}
+			assert.Error(t, step.loadBindings())
+		})
+	}
+}
+
+func TestGetReferencedResourceList(t *testing.T) {
+	pod1 := kubernetes.NewPod("pod1", testNamespace)
+	pod1.SetLabels(map[string]string{"app": "a"})
+	pod2 := kubernetes.NewPod("pod2", testNamespace)
+	pod2.SetLabels(map[string]string{"app": "b"})
+	otherPod := kubernetes.NewPod("pod3", "other")
+	otherPod.SetLabels(map[string]string{"app": "a"})
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod1, pod2, otherPod).Build()
+
+	names := func(items any) []string {
+		var result []string
+		for _, item := range items.([]any) {
+			result = append(result, item.(map[string]any)["metadata"].(map[string]any)["name"].(string))
+		}
+		return result
+	}
+
+	ref := harness.TestResourceRef{APIVersion: "v1", Kind: "Pod", Namespace: testNamespace, List: true, Ref: "pods"}
+	items, err := getReferencedResource(t.Context(), cl, ref)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"pod1", "pod2"}, names(items))
+
+	ref.LabelSelector = "app=a"
+	items, err = getReferencedResource(t.Context(), cl, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod1"}, names(items))
+
+	ref.LabelSelector = "app=c"
+	items, err = getReferencedResource(t.Context(), cl, ref)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestStepWait(t *testing.T) {
+	pod := kubernetes.WithStatus(t, kubernetes.NewPod("web", testNamespace), map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+	})
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+
+	step := Step{
+		Step: &harness.TestStep{Wait: []harness.TestWait{
+			{Type: harness.WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Name: "web", Condition: "Ready"},
+			{Type: harness.WaitTypeDeletion, APIVersion: "v1", Kind: "Pod", Selector: "app=gone"},
+		}},
+		Timeout: 1,
+		Client:  func(bool) (client.Client, error) { return cl, nil },
+		Logger:  testutils.NewTestLogger(t, ""),
+	}
+	require.NoError(t, step.Wait(t.Context(), testNamespace))
+
+	step.Step.Wait = []harness.TestWait{{Type: harness.WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Name: "web", Condition: "Ready", Status: "False"}}
+	assert.ErrorContains(t, step.Wait(t.Context(), testNamespace), `waiting for condition Ready=False of Pod web: timed out (result of last check was: "/v1, Kind=Pod web: condition Ready is True")`)
+
+	// The deadline of the step also limits the waits with a longer timeout of their own.
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	step.Step.Wait[0].Timeout = 30
+	start := time.Now()
+	assert.ErrorContains(t, step.Wait(ctx, testNamespace), "timed out")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	step.Step.Wait = []harness.TestWait{{Type: harness.WaitTypeLogs, Name: "web", Contains: "started"}}
+	assert.ErrorContains(t, step.Wait(t.Context(), testNamespace), "no REST config available")
+}
+
+func TestCheckAssertEvents(t *testing.T) {
+	reconcileFailed := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.1", Namespace: testNamespace},
+		Regarding:  corev1.ObjectReference{APIVersion: "example.com/v1", Kind: "MyApp", Name: "web"},
+		Type:       corev1.EventTypeWarning,
+		Reason:     "ReconcileFailed",
+		Note:       "exceeded quota: compute-resources",
+		Series:     &eventsv1.EventSeries{Count: 3},
+		EventTime:  metav1.NowMicro(),
+	}
+	scaled := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.2", Namespace: testNamespace},
+		Regarding:  corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		Type:       corev1.EventTypeNormal,
+		Reason:     "ScalingReplicaSet",
+		Note:       "Scaled up replica set web-5d4f8 to 1",
+		EventTime:  metav1.NowMicro(),
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(reconcileFailed, scaled).Build()
+
+	for name, tt := range map[string]struct {
+		event  harness.TestEvent
+		errMsg string
+	}{
+		"reason and type": {event: harness.TestEvent{Type: "Warning", Reason: "ReconcileFailed"}},
+		"series count":    {event: harness.TestEvent{Reason: "ReconcileFailed", MinCount: 3}},
+		"involved object": {event: harness.TestEvent{InvolvedObject: harness.TestEventObject{Kind: "Deployment", Name: "web"}, Message: `to \d+$`}},
+		"not enough": {
+			event:  harness.TestEvent{Reason: "ReconcileFailed", MinCount: 4},
+			errMsg: "event ReconcileFailed: found 3 occurrences in namespace world, expected at least 4",
+		},
+		"wrong type": {
+			event:  harness.TestEvent{Type: "Warning", Reason: "ScalingReplicaSet"},
+			errMsg: "found 0 occurrences",
+		},
+		"other namespace": {
+			event:  harness.TestEvent{Namespace: "other", Reason: "ReconcileFailed"},
+			errMsg: "found 0 occurrences in namespace other",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			step := Step{
+				Assert: &harness.TestAssert{Events: []harness.TestEvent{tt.event}},
+				Client: func(bool) (client.Client, error) { return cl, nil },
+				Logger: testutils.NewTestLogger(t, ""),
+			}
+			errs := step.CheckAssertEvents(t.Context(), testNamespace)
+			if tt.errMsg == "" {
+				assert.Empty(t, errs)
+			} else {
+				require.Len(t, errs, 1)
+				assert.ErrorContains(t, errs[0], tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCheckAssertLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/api/v1/namespaces/world/pods/web-0/log":
+			_, err = w.Write([]byte("starting\nlistening on :8080\n"))
+		case "/api/v1/namespaces/world/pods/web-1/log":
+			_, err = w.Write([]byte("starting\n"))
+		default:
+			http.NotFound(w, r)
+		}
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(srv.Close)
+
+	web0 := kubernetes.WithLabels(t, kubernetes.NewPod("web-0", testNamespace), map[string]string{"app": "web"})
+	web1 := kubernetes.WithLabels(t, kubernetes.NewPod("web-1", testNamespace), map[string]string{"app": "web"})
+	for _, pod := range []*unstructured.Unstructured{web0, web1} {
+		pod.Object["spec"] = map[string]any{"containers": []any{map[string]any{"name": "web"}}}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(web0, web1).Build()
+
+	step := Step{
+		Assert: &harness.TestAssert{},
+		Client: func(bool) (client.Client, error) { return cl, nil },
+		Config: func() (*rest.Config, error) { return &rest.Config{Host: srv.URL}, nil },
+		Logger: testutils.NewTestLogger(t, ""),
+	}
+
+	step.Assert.Logs = []harness.TestLogs{
+		{Pod: "web-0", OutputMatcher: harness.OutputMatcher{Regex: `listening on :\d+`}},
+	}
+	assert.Empty(t, step.CheckAssertLogs(t.Context(), testNamespace))
+
+	step.Assert.Logs = []harness.TestLogs{
+		{Selector: "app=web", OutputMatcher: harness.OutputMatcher{Contains: "listening"}},
+	}
+	errs := step.CheckAssertLogs(t.Context(), testNamespace)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "logs of pods with selector app=web: pod web-1: logs does not contain \"listening\"\nlast 50 lines of logs were:\nstarting")
+
+	step.Assert.Logs = []harness.TestLogs{
+		{Pod: "web-2", OutputMatcher: harness.OutputMatcher{Contains: "listening"}},
+	}
+	errs = step.CheckAssertLogs(t.Context(), testNamespace)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "logs of pod web-2: pod web-2 not found in namespace world")
+}
+
+func TestCheckExecResult(t *testing.T) {
+	two := 2
+	for name, tt := range map[string]struct {
+		exec   harness.TestExec
+		result kubernetes.ExecResult
+		errMsg string
+	}{
+		"success": {
+			exec:   harness.TestExec{Stdout: &harness.OutputMatcher{Contains: "web"}},
+			result: kubernetes.ExecResult{Stdout: "web-0\n"},
+		},
+		"expected exit code": {
+			exec:   harness.TestExec{ExitCode: &two},
+			result: kubernetes.ExecResult{ExitCode: 2},
+		},
+		"unexpected exit code": {
+			result: kubernetes.ExecResult{Stderr: "cat: missing: No such file or directory\n", ExitCode: 1},
+			errMsg: "exited with code 1, expected 0\nstderr was:\ncat: missing: No such file or directory\n",
+		},
+		"stdout mismatch": {
+			exec:   harness.TestExec{Stdout: &harness.OutputMatcher{Regex: "^db-"}},
+			result: kubernetes.ExecResult{Stdout: "web-0\n"},
+			errMsg: "stdout does not match regex \"^db-\"\nstdout was:\nweb-0\n",
+		},
+		"output not reported": {
+			exec:   harness.TestExec{Stderr: &harness.OutputMatcher{Equals: new("ok")}, SkipLogOutput: true},
+			result: kubernetes.ExecResult{Stderr: "secret"},
+			errMsg: "stderr is not equal to the expected value:",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := checkExecResult(tt.exec, tt.result)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), tt.errMsg), err.Error())
+			if tt.exec.SkipLogOutput {
+				assert.NotContains(t, err.Error(), "was:")
+			}
+		})
+	}
+}
+
+func TestStepExecWithoutConfig(t *testing.T) {
+	step := Step{
+		Step:   &harness.TestStep{Exec: []harness.TestExec{{Pod: "web-0", Command: []string{"true"}}}},
+		Logger: testutils.NewTestLogger(t, ""),
+	}
+	assert.ErrorContains(t, step.Exec(t.Context(), testNamespace), "no REST config available")
+}
+
+func TestCheckAssertExecWithoutTimeLeft(t *testing.T) {
+	step := Step{
+		Assert: &harness.TestAssert{Exec: []harness.TestExec{{Pod: "web-0", Command: []string{"true"}, Timeout: 5}}},
+		Logger: testutils.NewTestLogger(t, ""),
+	}
+	errs := step.CheckAssertExec(t.Context(), testNamespace, 0)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "no time left to run the command")
+	assert.NotContains(t, errs[0].Error(), "sec timeout")
+	assert.ErrorIs(t, errs[0], context.DeadlineExceeded, "running out of time must end the assertions")
+}
+
+func TestCollect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/world/pods/web-0/log" {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := fmt.Fprintf(w, "%s logs, tail=%s\n", r.URL.Query().Get("container"), r.URL.Query().Get("tailLines"))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(srv.Close)
+
+	pod := kubernetes.WithLabels(t, kubernetes.NewPod("web-0", testNamespace), map[string]string{"app": "web"})
+	pod.Object["spec"] = map[string]any{
+		"initContainers": []any{map[string]any{"name": "init"}},
+		"containers":     []any{map[string]any{"name": "web"}},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-0.1", Namespace: testNamespace},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-0"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		LastTimestamp:  metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod, event).Build()
+
+	artifacts := t.TempDir()
+	step := Step{
+		Name:  "deploy",
+		Index: 1,
+		Assert: &harness.TestAssert{Collectors: []*harness.TestCollector{
+			{Pod: "web-0"},
+			{Selector: "app=web", Container: "web"},
+			{Type: harness.CollectorTypeEvents},
+			{Cmd: "echo collected"},
+			{Type: "metrics"},
+			{Type: harness.CollectorTypeResources, Kinds: []string{"pod"}},
+			{Type: harness.CollectorTypeEvents, When: harness.CollectorWhenSuccess},
+		}},
+		Client:          func(bool) (client.Client, error) { return cl, nil },
+		DiscoveryClient: func() (discovery.DiscoveryInterface, error) { return k8sfake.DiscoveryClient(), nil },
+		Config:          func() (*rest.Config, error) { return &rest.Config{Host: srv.URL}, nil },
+		Logger:          testutils.NewTestLogger(t, ""),
+		TemplateEnv:     template.Env{ArtifactsDir: artifacts, SuiteName: "./e2e", CaseName: "collectors"},
+	}
+	step.Collect(testNamespace, true)
+
+	dir := filepath.Join(artifacts, "e2e", "collectors", "1-deploy")
+	readArtifact := func(name string) string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+	// The logs of a named pod are not limited, while those of selected pods default to the last 10 lines.
+	assert.Equal(t, "init logs, tail=\n", readArtifact("0-logs-world-web-0-init.log"))
+	assert.Equal(t, "web logs, tail=\n", readArtifact("0-logs-world-web-0-web.log"))
+	assert.Equal(t, "web logs, tail=10\n", readArtifact("1-logs-world-web-0-web.log"))
+	assert.Equal(t, "LAST SEEN              TYPE      REASON    OBJECT      MESSAGE\n"+
+		"2026-01-02T03:04:05Z   Warning   BackOff   pod/web-0   Back-off restarting failed container\n", readArtifact("2-events-world.log"))
+	assert.Equal(t, "collected\n", readArtifact("3-command.log"))
+	resources := readArtifact("5-resources-world.yaml")
+	assert.True(t, strings.HasPrefix(resources, "---\napiVersion: v1\nkind: Pod\n"), resources)
+	assert.Contains(t, resources, "name: web-0")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 6)
+	assert.Len(t, step.Artifacts, 6)
+
+	// Only the collectors which run on success run after a successful step.
+	step.Artifacts = nil
+	step.Collect(testNamespace, false)
+	assert.Equal(t, []string{filepath.Join(dir, "6-events-world.log")}, step.Artifacts)
+}
+
+func TestCollectTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(30 * time.Second):
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	pod := kubernetes.NewPod("web-0", testNamespace)
+	pod.Object["spec"] = map[string]any{"containers": []any{map[string]any{"name": "web"}}}
+	step := Step{
+		Timeout: 1,
+		Assert:  &harness.TestAssert{Collectors: []*harness.TestCollector{{Pod: "web-0"}}},
+		Client: func(bool) (client.Client, error) {
+			return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build(), nil
+		},
+		Config: func() (*rest.Config, error) { return &rest.Config{Host: srv.URL}, nil },
+		Logger: testutils.NewTestLogger(t, ""),
+	}
+
+	start := time.Now()
+	step.Collect(testNamespace, true)
+	assert.Less(t, time.Since(start), 10*time.Second, "the collectors must be limited by the step timeout")
+}
+
+// The default collectors are shared by the test cases running in parallel: run with -race.
+func TestCollectSharedDefaultCollectors(t *testing.T) {
+	collector := &harness.TestCollector{Type: "Command", Cmd: "true", When: "Always"}
+	require.NoError(t, collector.Validate())
+	shared := *collector
+
+	var wg sync.WaitGroup
+	for i := range 2 {
+		step := &Step{
+			Name:              "shared",
+			Index:             i,
+			DefaultCollectors: []*harness.TestCollector{collector},
+			Logger:            testutils.NewTestLogger(t, ""),
+		}
+		wg.Go(func() {
+			step.Collect(testNamespace, true)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, shared, *collector, "the shared collector must not be modified")
+}
+
+func TestRunCollectsOnEarlyFailure(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	artifacts := t.TempDir()
+	step := Step{
+		Name:  "setup",
+		Index: 0,
+		Step:  &harness.TestStep{Commands: []harness.Command{{Command: "false"}}},
+		// Suite and case collectors run even though the step has no TestAssert and stops before its assertions.
+		DefaultCollectors: []*harness.TestCollector{{Type: harness.CollectorTypeCommand, Cmd: "echo $NAMESPACE", When: harness.CollectorWhenFailure}},
+		Client:            func(bool) (client.Client, error) { return cl, nil },
+		DiscoveryClient:   func() (discovery.DiscoveryInterface, error) { return k8sfake.DiscoveryClient(), nil },
+		Logger:            testutils.NewTestLogger(t, ""),
+		TemplateEnv:       template.Env{ArtifactsDir: artifacts, SuiteName: "e2e", CaseName: "collectors"},
+	}
+	assert.NotEmpty(t, step.Run(t, testNamespace))
+
+	b, err := os.ReadFile(filepath.Join(artifacts, "e2e", "collectors", "0-setup", "0-command.log"))
+	require.NoError(t, err)
+	assert.Equal(t, testNamespace+"\n", string(b))
+}
@@ -0,0 +1,107 @@
+package step
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// Wait waits for the states declared in the TestStep, in order. Each wait is limited by its own timeout, if any,
+// and by the deadline of ctx, which is that of the whole step.
+func (s *Step) Wait(ctx context.Context, namespace string) error {
+	if s.Step == nil || len(s.Step.Wait) == 0 {
+		return nil
+	}
+
+	cl, err := s.Client(false)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range s.Step.Wait {
+		s.Logger.Logf("waiting for %s", w.String())
+		if err := s.wait(ctx, cl, w, namespace); err != nil {
+			return fmt.Errorf("waiting for %s: %w", w.String(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Step) wait(ctx context.Context, cl client.Client, w harness.TestWait, namespace string) error {
+	gvk, err := w.GroupVersionKind()
+	if err != nil {
+		return err
+	}
+	selector, err := labels.Parse(w.Selector)
+	if err != nil {
+		return err
+	}
+	if w.Namespace != "" {
+		namespace = w.Namespace
+	}
+	timeout := s.GetTimeout()
+	if w.Timeout > 0 {
+		timeout = w.Timeout
+	}
+	duration := time.Duration(timeout) * time.Second
+
+	var check kubernetes.StateCheck
+	switch w.Type {
+	case harness.WaitTypeCondition:
+		status := w.Status
+		if status == "" {
+			status = "True"
+		}
+		check = func(_ context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+			done, msg := kubernetes.ConditionMet(obj, w.Condition, status)
+			return done, msg, nil
+		}
+	case harness.WaitTypeRollout:
+		check = func(_ context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+			return kubernetes.RolloutComplete(obj)
+		}
+	case harness.WaitTypeJob:
+		check = func(_ context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+			return kubernetes.JobComplete(obj)
+		}
+	case harness.WaitTypeLogs:
+		cs, err := s.clientset()
+		if err != nil {
+			return err
+		}
+		check = func(ctx context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+			logs, err := kubernetes.PodLogs(ctx, cs, obj, w.Container)
+			if err != nil {
+				// Logs are unavailable until the containers have started.
+				return false, err.Error(), nil
+			}
+			if !strings.Contains(logs, w.Contains) {
+				return false, "logs do not contain the string yet", nil
+			}
+			return true, "", nil
+		}
+	case harness.WaitTypeDeletion:
+		return kubernetes.WaitForAbsence(ctx, cl, gvk, namespace, w.Name, selector, duration)
+	default:
+		return fmt.Errorf("wait type %q unknown", w.Type)
+	}
+	return kubernetes.WaitForState(ctx, cl, gvk, namespace, w.Name, selector, check, duration)
+}
+
+// clientset returns a typed client for the step's cluster, for the APIs not supported by the controller-runtime client.
+func (s *Step) clientset() (clientset.Interface, error) {
+	cfg, err := s.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return clientset.NewForConfig(cfg)
+}
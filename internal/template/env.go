@@ -12,13 +12,33 @@ import (
 	"github.com/Masterminds/sprig/v3"
 )
 
+// LookupFunc returns the live object with the given coordinates, or a list of objects if name is empty.
+// An empty map is returned if the object does not exist.
+type LookupFunc func(apiVersion, kind, namespace, name string) (map[string]any, error)
+
 // Env represents the data structure available to test file templates.
 type Env struct {
 	// Name of the namespace for the test.
 	Namespace string
+	// Name of the test suite, i.e. the test directory as listed in the TestSuite.
+	SuiteName string
+	// Name of the test case, including the matrix variables if any.
+	CaseName string
+	// Name of the test step, as set by its TestStep or derived from the name of its first apply file.
+	StepName string
+	// Index of the test step.
+	StepIndex int
+	// Absolute path of the directory to output artifacts to.
+	ArtifactsDir string
+	// Version of the Kubernetes API server, e.g. "v1.33.1".
+	KubernetesVersion string
+	// Group versions served by the Kubernetes API server, e.g. "apps/v1".
+	APIVersions []string
 	// Any variables defined when invoking `kuttl test`
 	// with --template-var name=value
 	Vars map[string]any
+	// Lookup backs the lookup template function. It is not available as data.
+	Lookup LookupFunc `json:"-"`
 	// Please keep docs/testing/templating.md in sync.
 }
 
@@ -32,12 +52,25 @@ func (e Env) Clone() (Env, error) {
 	if err := json.NewDecoder(&buf).Decode(&clone); err != nil {
 		return Env{}, err
 	}
+	clone.Lookup = e.Lookup
 	return clone, nil
 }
 
+// funcMap returns the functions available to templates.
+func (e Env) funcMap() template.FuncMap {
+	funcs := sprig.FuncMap()
+	funcs["lookup"] = func(apiVersion, kind, namespace, name string) (map[string]any, error) {
+		if e.Lookup == nil {
+			return map[string]any{}, nil
+		}
+		return e.Lookup(apiVersion, kind, namespace, name)
+	}
+	return funcs
+}
+
 // LoadAndExpand loads a template file and expands it with the provided environment variables.
 func LoadAndExpand(fileName string, env Env) (io.Reader, error) {
-	tpl, err := template.New(filepath.Base(fileName)).Funcs(sprig.FuncMap()).ParseFiles(fileName)
+	tpl, err := template.New(filepath.Base(fileName)).Funcs(env.funcMap()).ParseFiles(fileName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse text/template file %q: %w", fileName, err)
 	}
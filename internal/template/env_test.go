@@ -1,6 +1,9 @@
 package template
 
 import (
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,3 +38,32 @@ func TestEnv_Clone(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadAndExpand_Lookup(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "cm.gotmpl.yaml")
+	require.NoError(t, os.WriteFile(fileName, []byte(`uid: {{ (lookup "v1" "ConfigMap" .Namespace "cm").metadata.uid }}
+missing: {{ lookup "v1" "ConfigMap" .Namespace "missing" | len }}
+`), 0600))
+
+	env := Env{
+		Namespace: "ns",
+		Lookup: func(apiVersion, kind, namespace, name string) (map[string]any, error) {
+			if apiVersion == "v1" && kind == "ConfigMap" && namespace == "ns" && name == "cm" {
+				return map[string]any{"metadata": map[string]any{"uid": "1234"}}, nil
+			}
+			return map[string]any{}, nil
+		},
+	}
+	out, err := LoadAndExpand(fileName, env)
+	require.NoError(t, err)
+	content, err := io.ReadAll(out)
+	require.NoError(t, err)
+	assert.Equal(t, "uid: 1234\nmissing: 0\n", string(content))
+
+	env.Lookup = nil
+	out, err = LoadAndExpand(fileName, env)
+	require.NoError(t, err)
+	content, err = io.ReadAll(out)
+	require.NoError(t, err)
+	assert.Equal(t, "uid: <no value>\nmissing: 0\n", string(content))
+}
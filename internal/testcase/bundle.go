@@ -0,0 +1,172 @@
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	eventutils "github.com/kudobuilder/kuttl/internal/utils/events"
+)
+
+// collectFailureBundle gathers a debug bundle of the test namespace in the artifacts directory of the test case:
+// the objects of the namespace, its events, the logs of all the containers of its pods (including the previous
+// instance of restarted containers), and the status of the objects which are not ready.
+// Failures to gather parts of the bundle are logged.
+func (c *Case) collectFailureBundle(ctx context.Context) {
+	dir := c.artifactsDir()
+	if c.failureBundle.Disabled || dir == "" {
+		return
+	}
+	c.logger.Logf("gathering failure bundle in %s", dir)
+
+	errs := []error{
+		c.bundleResources(ctx, dir),
+		c.bundleEvents(ctx, dir),
+		c.bundleLogs(ctx, dir),
+	}
+	if err := errors.Join(errs...); err != nil {
+		c.logger.Warnf("failure bundle is incomplete: %v", err)
+	}
+	c.logger.Flush()
+}
+
+// bundleResources dumps the objects of the namespace to resources.yaml, and describes the status of those which are
+// not ready in status.txt. The objects which are not ready are also listed in the log.
+func (c *Case) bundleResources(ctx context.Context, dir string) error {
+	dClient, err := c.getDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	cl, err := c.getClient(false)
+	if err != nil {
+		return err
+	}
+	kinds, err := kubernetes.NamespacedKinds(dClient, c.failureBundle.Kinds...)
+	if err != nil {
+		return err
+	}
+	objects, snapshotErr := kubernetes.Snapshot(ctx, cl, kinds, c.ns.name, labels.Everything())
+
+	var resources bytes.Buffer
+	if err := kubernetes.MarshalSnapshotYAML(objects, &resources); err != nil {
+		return errors.Join(snapshotErr, err)
+	}
+	errs := []error{snapshotErr}
+	var status bytes.Buffer
+	for i := range objects {
+		if ready, reason := kubernetes.Ready(&objects[i]); !ready {
+			id := kubernetes.ResourceID(&objects[i])
+			c.logger.Logf("%s is not ready: %s", id, reason)
+			description, err := kubernetes.DescribeStatus(&objects[i])
+			if err != nil {
+				// The object is still listed, without its description.
+				errs = append(errs, fmt.Errorf("describing the status of %s: %w", id, err))
+				fmt.Fprintf(&status, "%s: %s\n", id, reason)
+				continue
+			}
+			fmt.Fprintf(&status, "%s: %s\n%s\n", id, reason, description)
+		}
+	}
+	return errors.Join(append(errs,
+		writeArtifact(dir, "resources.yaml", resources.Bytes()),
+		writeArtifact(dir, "status.txt", status.Bytes()))...)
+}
+
+// bundleEvents writes the events of the namespace to events.log.
+func (c *Case) bundleEvents(ctx context.Context, dir string) error {
+	cl, err := c.getClient(false)
+	if err != nil {
+		return err
+	}
+	events, err := eventutils.List(ctx, cl, c.ns.name)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := eventutils.WriteTable(&buf, events); err != nil {
+		return err
+	}
+	return writeArtifact(dir, "events.log", buf.Bytes())
+}
+
+// bundleLogs writes the logs of each container of each pod of the namespace to logs/<pod>-<container>.log,
+// and those of the previous instance of restarted containers to logs/<pod>-<container>-previous.log.
+func (c *Case) bundleLogs(ctx context.Context, dir string) error {
+	if c.getConfig == nil {
+		return errors.New("no REST config available for collecting pod logs")
+	}
+	cfg, err := c.getConfig()
+	if err != nil {
+		return err
+	}
+	cs, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	cl, err := c.getClient(false)
+	if err != nil {
+		return err
+	}
+	pods, err := kubernetes.Pods(ctx, cl, c.ns.name, "", labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var tailLines *int64
+	if tail := int64(c.failureBundle.TailLines); tail > 0 {
+		tailLines = &tail
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		containers, err := kubernetes.AllContainerNames(&pod)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+			continue
+		}
+		for _, container := range containers {
+			previous := []bool{false}
+			restarts, err := kubernetes.RestartCount(&pod, container)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+			} else if restarts > 0 {
+				previous = append(previous, true)
+			}
+			for _, p := range previous {
+				logs, err := kubernetes.ContainerLogs(ctx, cs, c.ns.name, pod.GetName(), &corev1.PodLogOptions{
+					Container: container,
+					Previous:  p,
+					TailLines: tailLines,
+				})
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				name := fmt.Sprintf("%s-%s.log", pod.GetName(), container)
+				if p {
+					name = fmt.Sprintf("%s-%s-previous.log", pod.GetName(), container)
+				}
+				if err := writeArtifact(filepath.Join(dir, "logs"), name, []byte(logs)); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeArtifact writes content to the named file in dir, creating dir if needed.
+func writeArtifact(dir, name string, content []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating artifacts directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, name), content, 0644)
+}
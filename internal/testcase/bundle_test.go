@@ -0,0 +1,137 @@
+package testcase
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	k8sfake "github.com/kudobuilder/kuttl/internal/kubernetes/fake"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+func TestCollectFailureBundle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/ns/pods/web/log" {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := fmt.Fprintf(w, "%s logs, previous=%s\n", r.URL.Query().Get("container"), r.URL.Query().Get("previous"))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(srv.Close)
+
+	pod := kubernetes.NewPod("web", "ns")
+	pod.Object["spec"] = map[string]any{"containers": []any{map[string]any{"name": "web"}, map[string]any{"name": "proxy"}}}
+	pod.Object["status"] = map[string]any{
+		"phase": "Running",
+		"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "message": "containers with unready status: [web]"},
+		},
+		"containerStatuses": []any{
+			map[string]any{"name": "web", "restartCount": int64(1)},
+			map[string]any{"name": "proxy", "restartCount": int64(0)},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "ns"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod, event).Build()
+
+	artifactsDir := t.TempDir()
+	c := NewCase("failing", "", WithNamespace("ns"), WithArtifactsDir(artifactsDir), WithSuiteName("e2e"),
+		WithClients(
+			func(bool) (client.Client, error) { return cl, nil },
+			func() (discovery.DiscoveryInterface, error) { return k8sfake.DiscoveryClient(), nil },
+		),
+		WithConfig(func() (*rest.Config, error) { return &rest.Config{Host: srv.URL}, nil }),
+	)
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+	c.collectFailureBundle(t.Context())
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(artifactsDir, "e2e", "failing", name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	assert.Contains(t, read("resources.yaml"), "kind: Pod\nmetadata:\n")
+	assert.Contains(t, read("status.txt"), "Pod:ns/web: condition Ready is False: containers with unready status: [web]\n")
+	assert.Contains(t, read("events.log"), "BackOff   pod/web   Back-off restarting failed container")
+	assert.Equal(t, "web logs, previous=\n", read("logs/web-web.log"))
+	assert.Equal(t, "web logs, previous=true\n", read("logs/web-web-previous.log"))
+	assert.Equal(t, "proxy logs, previous=\n", read("logs/web-proxy.log"))
+	assert.NoFileExists(t, filepath.Join(artifactsDir, "e2e", "failing", "logs", "web-proxy-previous.log"))
+}
+
+func TestCollectFailureBundleMalformedStatus(t *testing.T) {
+	// Deployments are not registered in the scheme, so that the fake client keeps their malformed status.
+	broken := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "broken", "namespace": "ns"},
+		"status":     map[string]any{"conditions": "Available"},
+	}}
+	web := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web", "namespace": "ns", "generation": int64(1)},
+		"spec":       map[string]any{"replicas": int64(1)},
+		"status":     map[string]any{"observedGeneration": int64(1), "replicas": int64(1), "updatedReplicas": int64(0)},
+	}}
+	cl := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(broken, web).Build()
+
+	artifactsDir := t.TempDir()
+	c := NewCase("failing", "", WithNamespace("ns"), WithArtifactsDir(artifactsDir), WithSuiteName("e2e"),
+		WithClients(
+			func(bool) (client.Client, error) { return cl, nil },
+			func() (discovery.DiscoveryInterface, error) { return k8sfake.DiscoveryClient(), nil },
+		),
+		WithFailureBundle(harness.FailureBundle{Kinds: []string{"deployment"}}),
+	)
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+
+	dir := filepath.Join(artifactsDir, "e2e", "failing")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	err := c.bundleResources(t.Context(), dir)
+	assert.ErrorContains(t, err, "describing the status of Deployment:ns/broken")
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	assert.Contains(t, read("resources.yaml"), "name: web\n")
+	status := read("status.txt")
+	assert.Contains(t, status, "Deployment:ns/broken: ")
+	assert.Contains(t, status, "Deployment:ns/web: ", "the objects after a malformed one must be described")
+}
+
+func TestCollectFailureBundleDisabled(t *testing.T) {
+	artifactsDir := t.TempDir()
+	c := NewCase("failing", "", WithArtifactsDir(artifactsDir), WithFailureBundle(harness.FailureBundle{Disabled: true}))
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+	c.collectFailureBundle(t.Context())
+
+	entries, err := os.ReadDir(artifactsDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -3,14 +3,15 @@ package testcase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"sort"
 	"testing"
 	"time"
 
 	petname "github.com/dustinkirkland/golang-petname"
-	"github.com/thoas/go-funk"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,10 +19,12 @@ import (
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kfile "github.com/kudobuilder/kuttl/internal/file"
 	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	"github.com/kudobuilder/kuttl/internal/progress"
 	"github.com/kudobuilder/kuttl/internal/report"
 	"github.com/kudobuilder/kuttl/internal/step"
 	"github.com/kudobuilder/kuttl/internal/template"
@@ -33,6 +36,7 @@ import (
 
 type getClientFuncType func(forceNew bool) (client.Client, error)
 type getDiscoveryClientFuncType func() (discovery.DiscoveryInterface, error)
+type getConfigFuncType func() (*rest.Config, error)
 
 // CaseOption represents a functional option for configuring a Case.
 type CaseOption func(*Case)
@@ -66,13 +70,6 @@ func WithTimeout(timeout int) CaseOption {
 	}
 }
 
-// WithLogSuppressions sets the list of log types to suppress.
-func WithLogSuppressions(suppressions []string) CaseOption {
-	return func(c *Case) {
-		c.suppressions = suppressions
-	}
-}
-
 // WithIgnoreFiles sets the list of file patterns to ignore.
 func WithIgnoreFiles(patterns []string) CaseOption {
 	return func(c *Case) {
@@ -90,7 +87,51 @@ func WithRunLabels(runLabels labels.Set) CaseOption {
 // WithTemplateVars returns a CaseOption that sets template variables for the test case.
 func WithTemplateVars(vars map[string]any) CaseOption {
 	return func(c *Case) {
-		c.templateEnv = template.Env{Vars: vars}
+		c.templateEnv.Vars = vars
+	}
+}
+
+// WithSuiteName sets the name of the test suite the test case belongs to, as exposed to templates.
+func WithSuiteName(name string) CaseOption {
+	return func(c *Case) {
+		c.templateEnv.SuiteName = name
+	}
+}
+
+// WithArtifactsDir sets the artifacts directory, as exposed to templates.
+func WithArtifactsDir(dir string) CaseOption {
+	return func(c *Case) {
+		c.templateEnv.ArtifactsDir = dir
+	}
+}
+
+// WithFailureBundle sets the configuration of the debug bundle gathered when the test case fails.
+func WithFailureBundle(bundle v1beta1.FailureBundle) CaseOption {
+	return func(c *Case) {
+		c.failureBundle = bundle
+	}
+}
+
+// WithEventRecording sets the configuration of the recording of the events of the test namespace.
+func WithEventRecording(recording v1beta1.EventRecording) CaseOption {
+	return func(c *Case) {
+		c.eventRecording = recording
+	}
+}
+
+// WithCollectors sets the collectors run when any step of the test case fails,
+// in addition to those of the step's TestAssert.
+func WithCollectors(collectors []*v1beta1.TestCollector) CaseOption {
+	return func(c *Case) {
+		c.collectors = collectors
+	}
+}
+
+// WithMatrixVars sets the variables of a single matrix combination this test case runs with.
+// They take precedence over template variables of the same name, and are reflected in the test case name.
+func WithMatrixVars(vars map[string]string) CaseOption {
+	return func(c *Case) {
+		c.matrixVars = vars
 	}
 }
 
@@ -102,18 +143,29 @@ func WithClients(getClientFunc getClientFuncType, getDiscoveryClientFunc getDisc
 	}
 }
 
+// WithConfig sets the function returning the REST config of the cluster, for clients other than the default one.
+func WithConfig(getConfigFunc getConfigFuncType) CaseOption {
+	return func(c *Case) {
+		c.getConfig = getConfigFunc
+	}
+}
+
 // Case contains all the test steps and the Kubernetes client and other global configuration
 // for a test. It represents a leaf directory containing test step files.
 // Case lifecycle:
 //  1. gets created (directly by Harness.LoadTests()) before the test's dedicated testing.T
 //     comes to file. At this point the namespace name is determined.
 //     The following steps are in the scope of the testing.T:
-//  2. has .SetLogger() called to assign a logger
-//  3. has .LoadTestSteps() called
+//  2. has .SetLogger() called to assign a logger, and optionally .SetProgress() to show its steps in the live view
+//  3. has .LoadTestSteps() called, which loads the steps without templates
 //  4. has .Run() called, which:
 //     4a. calls setup(), which: prepares the clients unless lazy-loaded, and creates their namespaces if needed
-//     (and in this case also schedules namespace deletion for test cleanup time)
-//     4b. for each step: sets the step up, prepares its client if lazy-loaded, and runs the step
+//     (and in this case also schedules namespace deletion for test cleanup time), starts recording events,
+//     then completes the template environment with cluster facts and the lookup function
+//     4b. for each step: loads the step if it has templates, sets it up, prepares its client if lazy-loaded
+//     or not prepared yet, and runs the step
+//     4c. if a step fails, gathers the failure bundle into the artifacts directory, and stops
+//     4d. lists the events of the test namespace, and writes those recorded to the artifacts directory
 type Case struct {
 	steps              []*step.Step
 	name               string
@@ -124,14 +176,31 @@ type Case struct {
 	ns                 *namespace
 	getClient          getClientFuncType
 	getDiscoveryClient getDiscoveryClientFuncType
+	getConfig          getConfigFuncType
 
 	logger testutils.Logger
-	// List of log types which should be suppressed.
-	suppressions []string
+	// Test case in the live view of the running test cases, if shown.
+	progress *progress.Case
 	// List of file patterns to ignore when collecting test steps.
 	ignoreFiles []string
+	// Configuration of the debug bundle gathered when the test case fails.
+	failureBundle v1beta1.FailureBundle
+	// Collectors run when any step fails, in addition to those of the step's TestAssert.
+	collectors []*v1beta1.TestCollector
+	// Configuration of the recording of the events of the test namespace.
+	eventRecording v1beta1.EventRecording
+	// Recorder of the events of the test namespace, if recording.
+	events *eventutils.Recorder
 	// Caution: the Vars element of this struct may be shared with other Case objects.
 	templateEnv template.Env
+	// Variables of the matrix combination this test case runs with, if any.
+	matrixVars map[string]string
+	// Files of each test step, kept for loading the steps with templates once the cluster state is known.
+	stepFiles map[int64][]string
+	// Kubeconfigs of the clusters the test namespace has been created in, the default one being empty.
+	namespaceClusters map[string]bool
+	// Variables captured by the test steps, e.g. from command output.
+	variables testutils.Variables
 }
 
 // namespace contains information about namespace name and its provenance.
@@ -142,7 +211,7 @@ type namespace struct {
 
 // NewCase returns a new test case object.
 func NewCase(name string, parentPath string, options ...CaseOption) *Case {
-	c := &Case{name: name, dir: filepath.Join(parentPath, name)}
+	c := &Case{name: name, dir: filepath.Join(parentPath, name), variables: testutils.Variables{}}
 
 	for _, option := range options {
 		option(c)
@@ -157,6 +226,19 @@ func NewCase(name string, parentPath string, options ...CaseOption) *Case {
 
 	c.templateEnv.Namespace = c.ns.name
 
+	if len(c.matrixVars) > 0 {
+		c.name = variantName(name, c.matrixVars)
+		vars := make(map[string]any, len(c.templateEnv.Vars)+len(c.matrixVars))
+		for k, v := range c.templateEnv.Vars {
+			vars[k] = v
+		}
+		for k, v := range c.matrixVars {
+			vars[k] = v
+		}
+		c.templateEnv.Vars = vars
+	}
+	c.templateEnv.CaseName = c.name
+
 	return c
 }
 
@@ -209,7 +291,7 @@ func (c *Case) deleteNamespace(cl clientWithKubeConfig) error {
 type T interface {
 	Context() context.Context
 	Cleanup(f func())
-	Error(args ...any)
+	Fail()
 }
 
 func (c *Case) createNamespace(test T, cl clientWithKubeConfig) error {
@@ -242,7 +324,7 @@ func (c *Case) createNamespace(test T, cl clientWithKubeConfig) error {
 				cl.Logf("Skipping deletion of pre-existing user supplied namespace %s", c.ns.name)
 			} else {
 				if err := c.deleteNamespace(cl); err != nil {
-					test.Error(err)
+					c.fail(test, err)
 				}
 			}
 		})
@@ -280,19 +362,33 @@ func (c *Case) didNsExist(ctx context.Context, err error, cl clientWithKubeConfi
 }
 
 func (c *Case) maybeReportEvents() {
-	if funk.Contains(c.suppressions, "events") {
+	if c.logger.Suppresses(testutils.LogEvents) {
 		c.logger.Logf("skipping kubernetes event logging")
 		return
 	}
 	ctx := context.TODO()
 	cl, err := c.getClient(false)
 	if err != nil {
-		c.logger.Log("Failed to collect events for %s in ns %s: %v", c.name, c.ns.name, err)
+		c.logger.Warnf("Failed to collect events for %s in ns %s: %v", c.name, c.ns.name, err)
 		return
 	}
 	eventutils.CollectAndLog(ctx, cl, c.ns.name, c.name, c.logger)
 }
 
+// fail logs errs as errors through the logger of the test case, so that they are written in its format,
+// and marks the test as failed. The diffs of failed assertions are not logged if the logger suppresses them.
+func (c *Case) fail(test interface{ Fail() }, errs ...error) {
+	for _, err := range errs {
+		var diffErr *step.DiffError
+		if errors.As(err, &diffErr) && c.logger.Suppresses(testutils.LogDiffs) {
+			continue
+		}
+		c.logger.Errorf("%v", err)
+	}
+	c.logger.Flush()
+	test.Fail()
+}
+
 // Run runs a test case including all of its steps.
 func (c *Case) Run(test *testing.T, rep report.TestReporter) {
 	defer rep.Done()
@@ -300,45 +396,71 @@ func (c *Case) Run(test *testing.T, rep report.TestReporter) {
 	setupReport := rep.Step("setup")
 	if err := c.setup(test); err != nil {
 		setupReport.Failure(err.Error())
-		test.Fatal(err)
+		c.fail(test, err)
+		test.FailNow()
 	}
 
-	for _, testStep := range c.steps {
+	for i, testStep := range c.steps {
+		// Load the step so that its templates see the cluster and the objects created by the previous steps.
+		// Steps without templates do not depend on them, so they are loaded with the test case.
+		var reloadErr error
+		if testStep.Templated {
+			var reloaded *step.Step
+			if reloaded, reloadErr = c.loadTestStep(int64(testStep.Index), c.stepFiles[int64(testStep.Index)]); reloadErr == nil {
+				testStep = reloaded
+				c.steps[i] = testStep
+			}
+		}
+
 		stepReport := rep.Step("step " + testStep.String())
-		testStep.Setup(c.logger, c.getClient, c.getDiscoveryClient)
+		c.setEventStep(testStep.String())
+		c.progress.Step(testStep.String())
+		testStep.Setup(c.logger, c.getClient, c.getDiscoveryClient, c.getConfig)
 		stepReport.AddAssertions(len(testStep.Asserts))
 		stepReport.AddAssertions(len(testStep.Errors))
 
 		var errs []error
+		if reloadErr != nil {
+			errs = append(errs, fmt.Errorf("failed to load step: %w", reloadErr))
+		}
 
-		// Set-up client/namespace for lazy-loaded Kubeconfig
-		if testStep.KubeconfigLoading == v1beta1.KubeconfigLoadingLazy {
+		// Set-up client/namespace for lazy-loaded Kubeconfig, and for the kubeconfig of a step with templates,
+		// which is only known once the step is loaded.
+		lazy := testStep.KubeconfigLoading == v1beta1.KubeconfigLoadingLazy
+		if len(errs) == 0 && (lazy || !c.namespaceClusters[testStep.Kubeconfig]) {
 			cl, err := testStep.Client(false)
-			if err != nil {
+			switch {
+			case err != nil && lazy:
 				errs = append(errs, fmt.Errorf("failed to lazy-load kubeconfig: %w", err))
-			} else if err = c.createNamespace(test, clientWithKubeConfig{cl, testStep.Kubeconfig, c.logger}); err != nil {
-				errs = append(errs, err)
+			case err != nil:
+				errs = append(errs, fmt.Errorf("failed to load kubeconfig: %w", err))
+			default:
+				if err = c.createNamespace(test, clientWithKubeConfig{cl, testStep.Kubeconfig, c.logger}); err != nil {
+					errs = append(errs, err)
+				} else if !lazy {
+					c.namespaceClusters[testStep.Kubeconfig] = true
+				}
 			}
 		}
 
 		// Run test case only if no setup errors are encountered
 		if len(errs) == 0 {
 			errs = append(errs, testStep.Run(test, c.ns.name)...)
+			stepReport.AddAttachments(testStep.Artifacts...)
 		}
 
 		if len(errs) > 0 {
 			caseErr := fmt.Errorf("failed in step %s", testStep.String())
 			stepReport.Failure(caseErr.Error(), errs...)
 
-			test.Error(caseErr)
-			for _, err := range errs {
-				test.Error(err)
-			}
+			c.fail(test, append([]error{caseErr}, errs...)...)
+			c.collectFailureBundle(test.Context())
 			break
 		}
 	}
 
-	c.maybeReportEvents()
+	// The events of the whole test case are attached to the setup report, which every test case has.
+	c.reportEvents(setupReport)
 }
 
 func (c *Case) setup(test *testing.T) error {
@@ -347,15 +469,44 @@ func (c *Case) setup(test *testing.T) error {
 		return err
 	}
 
+	c.namespaceClusters = map[string]bool{}
 	for _, cl := range clients {
 		if err := c.createNamespace(test, cl); err != nil {
 			return err
 		}
+		c.namespaceClusters[cl.kubeConfigPath] = true
 	}
+	c.startEventRecorder(test.Context())
+	c.setupTemplateEnv(test.Context())
+
 	return nil
 }
 
-// Returns clients for all steps other than the lazy loaded ones.
+// setupTemplateEnv fills in the parts of the template environment which require access to the cluster.
+// The facts of the cluster are only fetched if any step has templates, and without them the templates
+// see an empty Kubernetes version and API versions.
+func (c *Case) setupTemplateEnv(ctx context.Context) {
+	c.templateEnv.Lookup = func(apiVersion, kind, namespace, name string) (map[string]any, error) {
+		cl, err := c.getClient(false)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.Lookup(ctx, cl, apiVersion, kind, namespace, name)
+	}
+
+	if !slices.ContainsFunc(c.steps, func(s *step.Step) bool { return s.Templated }) {
+		return
+	}
+	dClient, err := c.getDiscoveryClient()
+	if err == nil {
+		c.templateEnv.KubernetesVersion, c.templateEnv.APIVersions, err = kubernetes.ServerFacts(dClient)
+	}
+	if err != nil {
+		c.logger.Warnf("failed to get the Kubernetes version and API versions of the cluster for templates: %v", err)
+	}
+}
+
+// Returns clients for all steps other than the lazy loaded ones and the ones with templates.
 // The returned slice will always contain at least a default client with an empty path.
 // However, there may be more clients, since each step may optionally specify a path to kubeconfig that should be used.
 // This is useful for multi-cluster or multi-context tests.
@@ -368,7 +519,8 @@ func (c *Case) getEagerClients() ([]clientWithKubeConfig, error) {
 	clients := map[string]client.Client{"": defaultClient}
 
 	for _, testStep := range c.steps {
-		if clients[testStep.Kubeconfig] != nil || testStep.KubeconfigLoading == v1beta1.KubeconfigLoadingLazy {
+		// The kubeconfig of the steps with templates is only known once they are loaded, right before they run.
+		if testStep.Templated || clients[testStep.Kubeconfig] != nil || testStep.KubeconfigLoading == v1beta1.KubeconfigLoadingLazy {
 			continue
 		}
 
@@ -390,7 +542,9 @@ func (c *Case) getEagerClients() ([]clientWithKubeConfig, error) {
 	return clientsWithPaths, nil
 }
 
-// LoadTestSteps loads all the test steps for a test case.
+// LoadTestSteps loads all the test steps for a test case. The templates of a step may depend on the cluster,
+// and on the bindings and variables captured by the previous steps, so the steps with templates are only loaded
+// right before they run.
 func (c *Case) LoadTestSteps() error {
 	testStepFiles, err := files.CollectTestStepFiles(c.dir, c.logger, c.ignoreFiles)
 	if err != nil {
@@ -400,24 +554,22 @@ func (c *Case) LoadTestSteps() error {
 	testSteps := []*step.Step{}
 
 	for index, files := range testStepFiles {
-		testStep := &step.Step{
-			Timeout:       c.timeout,
-			Index:         int(index),
-			SkipDelete:    c.skipDelete,
-			Dir:           c.dir,
-			TestRunLabels: c.runLabels,
-			Asserts:       []client.Object{},
-			Apply:         []client.Object{},
-			Errors:        []client.Object{},
-			TemplateEnv:   c.templateEnv,
+		if slices.ContainsFunc(files, func(file string) bool { return kfile.Parse(file).IsTemplate }) {
+			testSteps = append(testSteps, &step.Step{
+				Name:          firstApplyStepName(files),
+				Timeout:       c.timeout,
+				Index:         int(index),
+				SkipDelete:    c.skipDelete,
+				Dir:           c.dir,
+				TestRunLabels: c.runLabels,
+				Templated:     true,
+			})
+			continue
 		}
-
-		for _, file := range files {
-			if err := testStep.LoadYAML(kfile.Parse(file)); err != nil {
-				return err
-			}
+		testStep, err := c.loadTestStep(index, files)
+		if err != nil {
+			return err
 		}
-
 		testSteps = append(testSteps, testStep)
 	}
 
@@ -426,10 +578,77 @@ func (c *Case) LoadTestSteps() error {
 	})
 
 	c.steps = testSteps
+	c.stepFiles = testStepFiles
 	return nil
 }
 
+// loadTestStep loads a single test step from its files, expanding templates with the current template environment.
+// The templates of the step see the name of the step, which is only known once its files are loaded, since
+// TestFile selectors may skip files and a TestStep may override it. They are first expanded with the name of
+// the first apply file and expanded again if the name of the step turns out to be different.
+func (c *Case) loadTestStep(index int64, files []string) (*step.Step, error) {
+	stepName := firstApplyStepName(files)
+	testStep, err := c.loadTestStepNamed(index, files, stepName)
+	if err != nil || !testStep.Templated || testStep.Name == stepName {
+		return testStep, err
+	}
+	return c.loadTestStepNamed(index, files, testStep.Name)
+}
+
+// firstApplyStepName returns the step name of the first apply file among files, if any.
+func firstApplyStepName(files []string) string {
+	for _, file := range files {
+		if f := kfile.Parse(file); f.Type == kfile.TypeApply {
+			return f.StepName
+		}
+	}
+	return ""
+}
+
+// loadTestStepNamed loads a single test step from its files, expanding templates with stepName as the name of the step.
+func (c *Case) loadTestStepNamed(index int64, files []string, stepName string) (*step.Step, error) {
+	testStep := &step.Step{
+		Timeout:       c.timeout,
+		Index:         int(index),
+		SkipDelete:    c.skipDelete,
+		Dir:           c.dir,
+		TestRunLabels: c.runLabels,
+		Asserts:       []client.Object{},
+		Apply:         []client.Object{},
+		Errors:        []client.Object{},
+		TemplateEnv:   c.templateEnv,
+		Variables:     c.variables,
+
+		DefaultCollectors: c.collectors,
+	}
+	testStep.TemplateEnv.StepIndex = int(index)
+	testStep.TemplateEnv.StepName = stepName
+	if len(c.variables) > 0 {
+		vars := make(map[string]any, len(c.templateEnv.Vars)+len(c.variables))
+		for k, v := range c.templateEnv.Vars {
+			vars[k] = v
+		}
+		for k, v := range c.variables {
+			vars[k] = v
+		}
+		testStep.TemplateEnv.Vars = vars
+	}
+
+	for _, file := range files {
+		if err := testStep.LoadYAML(kfile.Parse(file)); err != nil {
+			return nil, err
+		}
+	}
+	testStep.TemplateEnv.StepName = testStep.Name
+	return testStep, nil
+}
+
 // SetLogger sets the logger for the test case.
 func (c *Case) SetLogger(logger testutils.Logger) {
 	c.logger = logger
 }
+
+// SetProgress sets the test case in the live view of the running test cases, which shows the running step.
+func (c *Case) SetProgress(p *progress.Case) {
+	c.progress = p
+}
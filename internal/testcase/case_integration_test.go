@@ -109,4 +109,5 @@ func (r *noOpReporter) Step(string) report.StepReporter {
 	return r
 }
 func (r *noOpReporter) AddAssertions(int)        {}
+func (r *noOpReporter) AddAttachments(...string) {}
 func (r *noOpReporter) Failure(string, ...error) {}
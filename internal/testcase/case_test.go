@@ -2,7 +2,10 @@ package testcase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,12 +16,14 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kudobuilder/kuttl/internal/kubernetes"
 	"github.com/kudobuilder/kuttl/internal/step"
+	"github.com/kudobuilder/kuttl/internal/template"
 	testutils "github.com/kudobuilder/kuttl/internal/utils"
 	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
 )
@@ -36,8 +41,9 @@ func TestLoadTestSteps(t *testing.T) {
 			labels.Set{},
 			[]step.Step{
 				{
-					Name:  "with-test-step-name-override",
-					Index: 0,
+					Name:        "with-test-step-name-override",
+					Index:       0,
+					TemplateEnv: template.Env{StepName: "with-test-step-name-override"},
 					Step: &harness.TestStep{
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "with-test-step-name-override",
@@ -68,8 +74,9 @@ func TestLoadTestSteps(t *testing.T) {
 					TestRunLabels: labels.Set{},
 				},
 				{
-					Name:  "test-assert",
-					Index: 1,
+					Name:        "test-assert",
+					Index:       1,
+					TemplateEnv: template.Env{StepName: "test-assert", StepIndex: 1},
 					Step: &harness.TestStep{
 						TypeMeta: metav1.TypeMeta{
 							Kind:       "TestStep",
@@ -113,8 +120,9 @@ func TestLoadTestSteps(t *testing.T) {
 					TestRunLabels: labels.Set{},
 				},
 				{
-					Name:  "pod",
-					Index: 2,
+					Name:        "pod",
+					Index:       2,
+					TemplateEnv: template.Env{StepName: "pod", StepIndex: 2},
 					Apply: []client.Object{
 						kubernetes.WithSpec(t, kubernetes.NewPod("test4", ""), map[string]interface{}{
 							"containers": []map[string]interface{}{
@@ -142,8 +150,9 @@ func TestLoadTestSteps(t *testing.T) {
 					TestRunLabels: labels.Set{},
 				},
 				{
-					Name:  "name-overridden",
-					Index: 3,
+					Name:        "name-overridden",
+					Index:       3,
+					TemplateEnv: template.Env{StepName: "name-overridden", StepIndex: 3},
 					Step: &harness.TestStep{
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "name-overridden",
@@ -189,8 +198,9 @@ func TestLoadTestSteps(t *testing.T) {
 			labels.Set{},
 			[]step.Step{
 				{
-					Name:  "pod",
-					Index: 0,
+					Name:        "pod",
+					Index:       0,
+					TemplateEnv: template.Env{StepName: "pod"},
 					Apply: []client.Object{
 						&unstructured.Unstructured{
 							Object: map[string]interface{}{
@@ -246,6 +256,7 @@ func TestLoadTestSteps(t *testing.T) {
 				{
 					Name:          "",
 					Index:         1,
+					TemplateEnv:   template.Env{StepIndex: 1},
 					TestRunLabels: labels.Set{},
 					Apply:         []client.Object{},
 					Asserts:       []client.Object{},
@@ -260,6 +271,7 @@ func TestLoadTestSteps(t *testing.T) {
 				{
 					Name:          "create-a",
 					Index:         1,
+					TemplateEnv:   template.Env{StepName: "create-a", StepIndex: 1},
 					TestRunLabels: labels.Set{"flavor": "a"},
 					Apply: []client.Object{
 						&unstructured.Unstructured{
@@ -300,6 +312,7 @@ func TestLoadTestSteps(t *testing.T) {
 				{
 					Name:          "create-b",
 					Index:         1,
+					TemplateEnv:   template.Env{StepName: "create-b", StepIndex: 1},
 					TestRunLabels: labels.Set{"flavor": "b"},
 					Apply: []client.Object{
 						&unstructured.Unstructured{
@@ -359,10 +372,118 @@ func TestLoadTestSteps(t *testing.T) {
 	}
 }
 
+func TestLoadTestStepWithVariables(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00-create.gotmpl.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Vars.prefix }}-{{ .Vars.captured }}
+`), 0600))
+
+	c := NewCase(filepath.Base(dir), filepath.Dir(dir), WithTemplateVars(map[string]any{"prefix": "cm", "captured": "unset"}))
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+	c.variables["captured"] = "foo"
+
+	testStep, err := c.loadTestStep(0, []string{filepath.Join(dir, "00-create.gotmpl.yaml")})
+	require.NoError(t, err)
+	require.Len(t, testStep.Apply, 1)
+	assert.True(t, testStep.Templated)
+	assert.Equal(t, "cm-foo", testStep.Apply[0].GetName())
+	assert.Equal(t, map[string]any{"prefix": "cm", "captured": "unset"}, c.templateEnv.Vars, "template variables must not be modified")
+
+	testStep.Variables["later"] = "bar"
+	assert.Equal(t, "bar", c.variables["later"], "variables must be shared with the test case")
+}
+
+func TestLoadTestStepsDefersTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00-create.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01-use.gotmpl.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ if not .Vars.captured }}{{ fail "captured must be set by step 0" }}{{ end }}{{ .Vars.captured }}-{{ .Vars.later.name }}
+`), 0600))
+
+	c := NewCase(filepath.Base(dir), filepath.Dir(dir))
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+
+	require.NoError(t, c.LoadTestSteps(), "steps with templates must not be expanded before they run")
+	require.Len(t, c.steps, 2)
+	assert.False(t, c.steps[0].Templated)
+	require.Len(t, c.steps[0].Apply, 1)
+	assert.True(t, c.steps[1].Templated)
+	assert.Equal(t, "use", c.steps[1].Name)
+	assert.Empty(t, c.steps[1].Apply)
+
+	c.variables["captured"] = "foo"
+	c.variables["later"] = map[string]any{"name": "bar"}
+	testStep, err := c.loadTestStep(1, c.stepFiles[1])
+	require.NoError(t, err)
+	require.Len(t, testStep.Apply, 1)
+	assert.Equal(t, "foo-bar", testStep.Apply[0].GetName())
+}
+
+func TestLoadTestStepNameInTemplates(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .StepName }}
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00-create-a.gotmpl.yaml"), content, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01-create-a.gotmpl.yaml"), content, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01-create-b.yaml"), []byte(`apiVersion: kuttl.dev/v1beta1
+kind: TestStep
+metadata:
+  name: renamed
+`), 0600))
+
+	c := NewCase(filepath.Base(dir), filepath.Dir(dir))
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+
+	testStep, err := c.loadTestStep(0, []string{filepath.Join(dir, "00-create-a.gotmpl.yaml")})
+	require.NoError(t, err)
+	require.Len(t, testStep.Apply, 1)
+	assert.Equal(t, "create-a", testStep.Apply[0].GetName())
+
+	testStep, err = c.loadTestStep(1, []string{filepath.Join(dir, "01-create-a.gotmpl.yaml"), filepath.Join(dir, "01-create-b.yaml")})
+	require.NoError(t, err)
+	require.Len(t, testStep.Apply, 1)
+	assert.Equal(t, "renamed", testStep.Name)
+	assert.Equal(t, "renamed", testStep.TemplateEnv.StepName)
+	assert.Equal(t, "renamed", testStep.Apply[0].GetName(), "templates must see the name set by the TestStep")
+}
+
+func TestSetupTemplateEnv(t *testing.T) {
+	discoveryCalls := 0
+	getDiscoveryClient := func() (discovery.DiscoveryInterface, error) {
+		discoveryCalls++
+		return nil, errors.New("discovery unavailable")
+	}
+	c := NewCase("case", "", WithClients(nil, getDiscoveryClient))
+	logger := &errorLogger{Logger: testutils.NewTestLogger(t, "")}
+	c.SetLogger(logger)
+
+	c.steps = []*step.Step{{}}
+	c.setupTemplateEnv(t.Context())
+	assert.Zero(t, discoveryCalls, "the cluster facts are only fetched for templates")
+	assert.NotNil(t, c.templateEnv.Lookup)
+
+	c.steps = append(c.steps, &step.Step{Templated: true})
+	c.setupTemplateEnv(t.Context())
+	assert.Equal(t, 1, discoveryCalls)
+	assert.Empty(t, logger.errors, "the failure to fetch the cluster facts is a warning")
+	assert.Empty(t, c.templateEnv.KubernetesVersion)
+}
+
 // testMock is an object useful for unit-testing Case.createNamespace().
 type testMock struct {
-	cleanup    func()
-	testErrors [][]any
+	cleanup func()
+	failed  bool
 }
 
 func (t *testMock) Context() context.Context {
@@ -373,8 +494,41 @@ func (t *testMock) Cleanup(f func()) {
 	t.cleanup = f
 }
 
-func (t *testMock) Error(args ...any) {
-	t.testErrors = append(t.testErrors, args)
+func (t *testMock) Fail() {
+	t.failed = true
+}
+
+// errorLogger records the arguments of the errors logged through it.
+type errorLogger struct {
+	testutils.Logger
+	errors [][]any
+}
+
+func (l *errorLogger) Errorf(format string, args ...any) {
+	l.errors = append(l.errors, args)
+	l.Logger.Errorf(format, args...)
+}
+
+func TestCase_failSuppressesDiffs(t *testing.T) {
+	diff := &step.DiffError{Diff: "-  invalid: key"}
+	assertErr := errors.New("resource Pod:world/hello: .spec.invalid: key is missing from map")
+
+	for _, test := range []struct {
+		suppressions []string
+		logged       [][]any
+	}{
+		{logged: [][]any{{diff}, {assertErr}}},
+		{suppressions: []string{testutils.LogDiffs}, logged: [][]any{{assertErr}}},
+	} {
+		c := NewCase("case", "")
+		logger := &errorLogger{Logger: testutils.NewTestLogger(t, "", testutils.WithSuppressions(test.suppressions))}
+		c.SetLogger(logger)
+		tm := &testMock{}
+
+		c.fail(tm, diff, assertErr)
+		assert.True(t, tm.failed)
+		assert.Equal(t, test.logged, logger.errors, test.suppressions)
+	}
 }
 
 func TestCase_createNamespace(t *testing.T) {
@@ -490,6 +644,8 @@ func TestCase_createNamespace(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			c := NewCase(name, "", tt.options...)
 			tm := &testMock{}
+			logger := &errorLogger{Logger: testutils.NewTestLogger(t, "")}
+			c.SetLogger(logger)
 			cl := tt.cl(t, c.ns.name)
 			if npc, ok := cl.(*noPermClient); ok {
 				npc.t = t
@@ -525,16 +681,18 @@ func TestCase_createNamespace(t *testing.T) {
 			}
 
 			if tt.expectedCleanupError != nil {
-				// Error should have been called once...
-				require.Len(t, tm.testErrors, 1)
+				assert.True(t, tm.failed)
+				// One error should have been logged...
+				require.Len(t, logger.errors, 1)
 				// ...with one parameter...
-				require.Len(t, tm.testErrors[0], 1)
+				require.Len(t, logger.errors[0], 1)
 				// ...which is an error.
-				err, ok := tm.testErrors[0][0].(error)
+				err, ok := logger.errors[0][0].(error)
 				require.True(t, ok)
 				assert.True(t, tt.expectedCleanupError(err))
 			} else {
-				assert.Empty(t, tm.testErrors)
+				assert.False(t, tm.failed)
+				assert.Empty(t, logger.errors)
 			}
 			ns = &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
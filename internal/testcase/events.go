@@ -0,0 +1,98 @@
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+
+	clientset "k8s.io/client-go/kubernetes"
+
+	kfile "github.com/kudobuilder/kuttl/internal/file"
+	"github.com/kudobuilder/kuttl/internal/report"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	eventutils "github.com/kudobuilder/kuttl/internal/utils/events"
+)
+
+// eventTimelineName is the name of the file the recorded events are written to, in the artifacts directory of the test case.
+const eventTimelineName = "events-timeline.log"
+
+// startEventRecorder starts recording the events of the test namespace until ctx is done, unless disabled.
+// If the recorder cannot be started, the events are only listed at the end of the test case.
+func (c *Case) startEventRecorder(ctx context.Context) {
+	if c.eventRecording.Disabled || c.getConfig == nil {
+		return
+	}
+	cfg, err := c.getConfig()
+	if err != nil {
+		c.logger.Warnf("failed to start recording events: %v", err)
+		return
+	}
+	cs, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		c.logger.Warnf("failed to start recording events: %v", err)
+		return
+	}
+	c.startEventRecorderWith(ctx, cs)
+}
+
+func (c *Case) startEventRecorderWith(ctx context.Context, cs clientset.Interface) {
+	recorder := eventutils.NewRecorder(eventutils.Filter{
+		Types:         c.eventRecording.Types,
+		Reasons:       c.eventRecording.Reasons,
+		InvolvedKinds: c.eventRecording.InvolvedKinds,
+	})
+	recorder.SetStep("setup")
+	if err := recorder.Start(ctx, cs, c.ns.name); err != nil {
+		c.logger.Warnf("failed to start recording events: %v", err)
+		return
+	}
+	c.events = recorder
+}
+
+// setEventStep tags the events recorded from now on with the name of the step.
+func (c *Case) setEventStep(step string) {
+	if c.events != nil {
+		c.events.SetStep(step)
+	}
+}
+
+// reportEvents lists the recorded events in the log, unless suppressed, and writes them to the artifacts directory
+// of the test case, attaching the file to rep. If events were not recorded, the current events are listed instead.
+func (c *Case) reportEvents(rep report.StepReporter) {
+	if c.events == nil {
+		c.maybeReportEvents()
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := eventutils.WriteTimeline(&buf, c.events.Records()); err != nil {
+		c.logger.Warnf("failed to format events: %v", err)
+		return
+	}
+	if c.logger.Suppresses(testutils.LogEvents) {
+		c.logger.Logf("skipping kubernetes event logging")
+	} else {
+		c.logger.Logf("%s events from ns %s:", c.name, c.ns.name)
+		if _, err := c.logger.Write(buf.Bytes()); err != nil {
+			c.logger.Warnf("failed to log events: %v", err)
+		}
+		c.logger.Flush()
+	}
+
+	if dir := c.artifactsDir(); dir != "" {
+		if err := writeArtifact(dir, eventTimelineName, buf.Bytes()); err != nil {
+			c.logger.Warnf("failed to write events: %v", err)
+			return
+		}
+		rep.AddAttachments(filepath.Join(dir, eventTimelineName))
+	}
+}
+
+// artifactsDir returns the directory the artifacts of the test case are written to,
+// or an empty string if no artifacts directory is configured.
+func (c *Case) artifactsDir() string {
+	if c.templateEnv.ArtifactsDir == "" {
+		return ""
+	}
+	return kfile.ArtifactsDir(c.templateEnv.ArtifactsDir, c.templateEnv.SuiteName, c.name)
+}
@@ -0,0 +1,71 @@
+package testcase
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kudobuilder/kuttl/internal/report"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+func TestReportEvents(t *testing.T) {
+	cs := k8sfake.NewClientset()
+	artifactsDir := t.TempDir()
+	c := NewCase("events", "", WithNamespace("ns"), WithArtifactsDir(artifactsDir), WithSuiteName("e2e"),
+		WithEventRecording(harness.EventRecording{Types: []string{"Warning"}}))
+	c.SetLogger(testutils.NewTestLogger(t, ""))
+	c.startEventRecorderWith(t.Context(), cs)
+	require.NotNil(t, c.events)
+
+	c.setEventStep("0-deploy")
+	for _, event := range []*corev1.Event{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web.1", Namespace: "ns"}, Type: corev1.EventTypeNormal, Reason: "Pulled"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web.2", Namespace: "ns"}, Type: corev1.EventTypeWarning, Reason: "BackOff"},
+	} {
+		_, err := cs.CoreV1().Events("ns").Create(t.Context(), event, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { return len(c.events.Records()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	suite := report.NewSuite("e2e", "test")
+	reporter := suite.NewTestReporter("events")
+	c.reportEvents(reporter.Step("setup"))
+	reporter.Done()
+
+	path := filepath.Join(artifactsDir, "e2e", "events", "events-timeline.log")
+	timeline, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(timeline), "0-deploy   Warning   BackOff")
+	assert.NotContains(t, string(timeline), "Pulled")
+	assert.Equal(t, "[[ATTACHMENT|"+path+"]]\n", suite.Testcases[0].SystemOut)
+}
+
+func TestReportEventsSuppressed(t *testing.T) {
+	cs := k8sfake.NewClientset()
+	var out bytes.Buffer
+	c := NewCase("events", "", WithNamespace("ns"), WithEventRecording(harness.EventRecording{Types: []string{"Warning"}}))
+	c.SetLogger(testutils.NewTestLogger(t, "", testutils.WithSuppressions([]string{"Events"}),
+		testutils.WithLogFormat(testutils.LogFormatJSON), testutils.WithLogOutput(&out)))
+	c.startEventRecorderWith(t.Context(), cs)
+	require.NotNil(t, c.events)
+
+	_, err := cs.CoreV1().Events("ns").Create(t.Context(), &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.1", Namespace: "ns"}, Type: corev1.EventTypeWarning, Reason: "BackOff",
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(c.events.Records()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	c.reportEvents(report.NewSuite("e2e", "test").NewTestReporter("events").Step("setup"))
+	assert.Contains(t, out.String(), "skipping kubernetes event logging")
+	assert.NotContains(t, out.String(), "BackOff", "suppressions are case-insensitive")
+}
@@ -0,0 +1,101 @@
+package testcase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	kfile "github.com/kudobuilder/kuttl/internal/file"
+	"github.com/kudobuilder/kuttl/internal/kubernetes"
+	"github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// LoadManifest loads the TestCase object from the case manifest file in dir.
+// It returns nil if the test case directory does not contain such a file.
+func LoadManifest(dir string) (*v1beta1.TestCase, error) {
+	path := filepath.Join(dir, kfile.CaseManifestName)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	objects, err := kubernetes.LoadYAMLFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", path, err)
+	}
+
+	var testCase *v1beta1.TestCase
+	for _, obj := range objects {
+		tc, ok := obj.(*v1beta1.TestCase)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %s in %s, only a TestCase is allowed", kubernetes.ResourceID(obj), path)
+		}
+		if testCase != nil {
+			return nil, fmt.Errorf("more than one TestCase object encountered in %s", path)
+		}
+		testCase = tc
+	}
+
+	if testCase == nil {
+		return nil, nil
+	}
+	for name, values := range testCase.Matrix {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix variable %q in %s has no values", name, path)
+		}
+	}
+	for i, collector := range testCase.Collectors {
+		if err := collector.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid collector %d in %s: %w", i, path, err)
+		}
+	}
+	return testCase, nil
+}
+
+// ExpandMatrix returns the list of variable sets described by matrix, one for each combination of values.
+// Variables are varied in alphabetical order, with the last one changing fastest.
+// An empty matrix expands to a single empty variable set.
+func ExpandMatrix(matrix map[string][]string) []map[string]string {
+	names := make([]string, 0, len(matrix))
+	for name := range matrix {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]string{{}}
+	for _, name := range names {
+		var expanded []map[string]string
+		for _, combination := range combinations {
+			for _, value := range matrix[name] {
+				next := make(map[string]string, len(combination)+1)
+				for k, v := range combination {
+					next[k] = v
+				}
+				next[name] = value
+				expanded = append(expanded, next)
+			}
+		}
+		combinations = expanded
+	}
+	return combinations
+}
+
+// pathSeparators replaces the path separators in matrix values, since the test case name is used as a path
+// for artifacts, and the first slash in a test name separates the test case from the step in the logs.
+var pathSeparators = strings.NewReplacer("/", "_", `\`, "_")
+
+// variantName returns the name of the test case suffixed with its matrix variables, e.g. upgrade[from=1.2,to=1.3].
+// Path separators in the variables are replaced with underscores.
+func variantName(name string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return name
+	}
+	pairs := make([]string, 0, len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, pathSeparators.Replace(fmt.Sprintf("%s=%s", k, v)))
+	}
+	sort.Strings(pairs)
+	return fmt.Sprintf("%s[%s]", name, strings.Join(pairs, ","))
+}
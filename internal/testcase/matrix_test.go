@@ -0,0 +1,101 @@
+package testcase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	for name, tt := range map[string]struct {
+		matrix   map[string][]string
+		expected []map[string]string
+	}{
+		"nil": {
+			expected: []map[string]string{{}},
+		},
+		"single variable": {
+			matrix:   map[string][]string{"version": {"1.2", "1.3"}},
+			expected: []map[string]string{{"version": "1.2"}, {"version": "1.3"}},
+		},
+		"combinations": {
+			matrix: map[string][]string{"to": {"1.3", "1.4"}, "from": {"1.2", "1.3"}},
+			expected: []map[string]string{
+				{"from": "1.2", "to": "1.3"},
+				{"from": "1.2", "to": "1.4"},
+				{"from": "1.3", "to": "1.3"},
+				{"from": "1.3", "to": "1.4"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExpandMatrix(tt.matrix))
+		})
+	}
+}
+
+func TestNewCaseWithMatrixVars(t *testing.T) {
+	c := NewCase("upgrade", "/tests",
+		WithTemplateVars(map[string]any{"from": "overridden", "other": 1}),
+		WithMatrixVars(map[string]string{"to": "1.3", "from": "1.2"}))
+
+	assert.Equal(t, "upgrade[from=1.2,to=1.3]", c.GetName())
+	assert.Equal(t, filepath.Join("/tests", "upgrade"), c.dir)
+	assert.Equal(t, map[string]any{"from": "1.2", "to": "1.3", "other": 1}, c.templateEnv.Vars)
+
+	c = NewCase("upgrade", "/tests", WithMatrixVars(map[string]string{"image": "quay.io/app:1.2", "dir": `..\data`}))
+	assert.Equal(t, `upgrade[dir=.._data,image=quay.io_app:1.2]`, c.GetName())
+	assert.Equal(t, map[string]any{"image": "quay.io/app:1.2", "dir": `..\data`}, c.templateEnv.Vars, "variables must not be modified")
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	manifest, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Nil(t, manifest)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kuttl-case.yaml"), []byte(`apiVersion: kuttl.dev/v1beta1
+kind: TestCase
+matrix:
+  from: ["1.2", "1.3"]
+`), 0600))
+	manifest, err = LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"from": {"1.2", "1.3"}}, manifest.Matrix)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kuttl-case.yaml"), []byte(`apiVersion: kuttl.dev/v1beta1
+kind: TestCase
+matrix:
+  from: []
+`), 0600))
+	_, err = LoadManifest(dir)
+	assert.ErrorContains(t, err, `matrix variable "from"`)
+}
+
+func TestLoadManifestCollectors(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kuttl-case.yaml"), []byte(`apiVersion: kuttl.dev/v1beta1
+kind: TestCase
+collectors:
+- selector: app=operator
+- type: events
+`), 0600))
+	manifest, err := LoadManifest(dir)
+	require.NoError(t, err)
+	require.Len(t, manifest.Collectors, 2)
+	assert.Equal(t, "app=operator", manifest.Collectors[0].Selector)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kuttl-case.yaml"), []byte(`apiVersion: kuttl.dev/v1beta1
+kind: TestCase
+collectors:
+- type: events
+- type: metrics
+`), 0600))
+	_, err = LoadManifest(dir)
+	assert.ErrorContains(t, err, "invalid collector 1")
+}
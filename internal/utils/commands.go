@@ -4,6 +4,7 @@ package utils //nolint:revive,nolintlint // apparently nolintlint is confused
 // Contains functions helpful for running commands.
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -34,6 +35,17 @@ func GetArgs(ctx context.Context, cmd harness.Command, namespace string, envMap
 	if cmd.Script != "" && cmd.Namespaced {
 		return nil, errors.New("script can not used 'namespaced', use the $NAMESPACE environment variable instead")
 	}
+	if cmd.OutputJSONPath != "" && cmd.OutputVar == "" {
+		return nil, errors.New("outputJSONPath requires outputVar to be set")
+	}
+	if cmd.OutputVar != "" {
+		if cmd.Background {
+			return nil, errors.New("outputVar can not be used with background commands")
+		}
+		if err := ValidateVariableName(cmd.OutputVar); err != nil {
+			return nil, err
+		}
+	}
 
 	if cmd.Script != "" {
 		// #nosec G204 sec is challenged by a variable being used by exec, but that is by design
@@ -72,13 +84,18 @@ func GetArgs(ctx context.Context, cmd harness.Command, namespace string, envMap
 // RunCommand runs a command with args.
 // args gets split on spaces (respecting quoted strings).
 // if the command is run in the background a reference to the process is returned for later cleanup.
-func RunCommand(ctx context.Context, namespace string, cmd harness.Command, cwd string, stdout io.Writer, stderr io.Writer, logger Logger, timeout int, kubeconfigOverride string) (*exec.Cmd, error) {
+// vars are passed to the command as environment variables, and receive its output if cmd.OutputVar is set.
+func RunCommand(ctx context.Context, namespace string, cmd harness.Command, cwd string, stdout io.Writer, stderr io.Writer, logger Logger, timeout int, kubeconfigOverride string, vars Variables) (*exec.Cmd, error) {
 	actualDir, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("command %q with %w", cmd.String(), err)
 	}
 
-	kuttlENV := make(map[string]string)
+	if cmd.OutputVar != "" && vars == nil {
+		return nil, fmt.Errorf("command %q: outputVar is not supported in this context", cmd.String())
+	}
+
+	kuttlENV := vars.Env()
 	kuttlENV["NAMESPACE"] = namespace
 	kuttlENV["KUBECONFIG"] = kubeconfigPath(actualDir, kubeconfigOverride)
 	kuttlENV["PATH"] = fmt.Sprintf("%s/bin/:%s", actualDir, os.Getenv("PATH"))
@@ -117,6 +134,15 @@ func RunCommand(ctx context.Context, namespace string, cmd harness.Command, cwd
 		builtCmd.Stdout = stdout
 		builtCmd.Stderr = stderr
 	}
+	var output *bytes.Buffer
+	if cmd.OutputVar != "" {
+		output = &bytes.Buffer{}
+		if builtCmd.Stdout != nil {
+			builtCmd.Stdout = io.MultiWriter(builtCmd.Stdout, output)
+		} else {
+			builtCmd.Stdout = output
+		}
+	}
 	builtCmd.Env = os.Environ()
 	for key, value := range kuttlENV {
 		builtCmd.Env = append(builtCmd.Env, fmt.Sprintf("%s=%s", key, value))
@@ -146,9 +172,25 @@ func RunCommand(ctx context.Context, namespace string, cmd harness.Command, cwd
 	if err != nil {
 		return nil, fmt.Errorf("command %q failed, %w", cmd.String(), err)
 	}
+	if output != nil {
+		value, err := extractOutput(output.Bytes(), cmd.OutputJSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("command %q: capturing output into %q: %w", cmd.String(), cmd.OutputVar, err)
+		}
+		vars[cmd.OutputVar] = value
+	}
 	return nil, nil
 }
 
+// CommandOutput returns the writer the output of commands is logged to: logger, or io.Discard if the output
+// of commands is suppressed.
+func CommandOutput(logger Logger) io.Writer {
+	if logger.Suppresses(LogCommands) {
+		return io.Discard
+	}
+	return logger
+}
+
 func kubeconfigPath(actualDir, override string) string {
 	if override != "" {
 		if filepath.IsAbs(override) {
@@ -159,36 +201,96 @@ func kubeconfigPath(actualDir, override string) string {
 	return fmt.Sprintf("%s/kubeconfig", actualDir)
 }
 
-// convertAssertCommand converts a set of TestAssertCommand to Commands so it all the existing functions can be used
+// convertAssertCommand converts a TestAssertCommand to a Command so all the existing functions can be used
 // that expect Commands data type.
-func convertAssertCommand(assertCommands []harness.TestAssertCommand, timeout int) (commands []harness.Command) {
-	commands = make([]harness.Command, 0, len(assertCommands))
-
-	for _, assertCommand := range assertCommands {
-		commands = append(commands, harness.Command{
-			Command:       assertCommand.Command,
-			Namespaced:    assertCommand.Namespaced,
-			Script:        assertCommand.Script,
-			SkipLogOutput: assertCommand.SkipLogOutput,
-			Timeout:       timeout,
-			// This fields will always be this constants for assertions
-			IgnoreFailure: false,
-			Background:    false,
-		})
-	}
-
-	return commands
+func convertAssertCommand(assertCommand harness.TestAssertCommand, timeout int) harness.Command {
+	// The command timeout limits a single run of the command within the remaining assert timeout, 0 meaning no limit.
+	if assertCommand.Timeout > 0 && (timeout <= 0 || assertCommand.Timeout < timeout) {
+		timeout = assertCommand.Timeout
+	}
+	return harness.Command{
+		Command:    assertCommand.Command,
+		Namespaced: assertCommand.Namespaced,
+		Script:     assertCommand.Script,
+		Timeout:    timeout,
+		// The output is captured for matching, and logged by runAssertCommand unless SkipLogOutput is set.
+		SkipLogOutput: false,
+		// This fields will always be this constants for assertions
+		IgnoreFailure: false,
+		Background:    false,
+	}
+}
+
+// RunAssertCommands runs a set of commands specified as TestAssertCommand, checking their exit code and output.
+// If any command fails, the following commands are skipped.
+func RunAssertCommands(ctx context.Context, logger Logger, namespace string, commands []harness.TestAssertCommand, workdir string, timeout int, kubeconfigOverride string, vars Variables) ([]*exec.Cmd, error) {
+	for i, assertCommand := range commands {
+		if err := runAssertCommand(ctx, logger, namespace, assertCommand, workdir, timeout, kubeconfigOverride, vars); err != nil {
+			if i+1 < len(commands) {
+				logger.Logf("command failure, skipping %d additional commands", len(commands)-i-1)
+			}
+			return nil, err
+		}
+		logger.Flush()
+	}
+	return nil, nil
 }
 
-// RunAssertCommands runs a set of commands specified as TestAssertCommand.
-func RunAssertCommands(ctx context.Context, logger Logger, namespace string, commands []harness.TestAssertCommand, workdir string, timeout int, kubeconfigOverride string) ([]*exec.Cmd, error) {
-	return RunCommands(ctx, logger, namespace, convertAssertCommand(commands, timeout), workdir, timeout, kubeconfigOverride)
+// runAssertCommand runs a single TestAssertCommand and checks its exit code and output against the expectations.
+func runAssertCommand(ctx context.Context, logger Logger, namespace string, assertCommand harness.TestAssertCommand, workdir string, timeout int, kubeconfigOverride string, vars Variables) error {
+	cmd := convertAssertCommand(assertCommand, timeout)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	var stdoutWriter, stderrWriter io.Writer = stdout, stderr
+	if !assertCommand.SkipLogOutput {
+		stdoutWriter = io.MultiWriter(CommandOutput(logger), stdout)
+		stderrWriter = io.MultiWriter(CommandOutput(logger), stderr)
+	}
+
+	expectedExitCode := 0
+	if assertCommand.ExitCode != nil {
+		expectedExitCode = *assertCommand.ExitCode
+	}
+
+	exitCode := 0
+	_, err := RunCommand(ctx, namespace, cmd, workdir, stdoutWriter, stderrWriter, logger, cmd.Timeout, kubeconfigOverride, vars)
+	var exerr *exec.ExitError
+	switch {
+	case errors.As(err, &exerr):
+		exitCode = exerr.ExitCode()
+	case errors.Is(err, context.DeadlineExceeded) && cmd.Timeout != timeout:
+		// Only this run of the command timed out, not the whole assert, so it should be retried.
+		return fmt.Errorf("command %q did not finish within its %d sec timeout", cmd.String(), cmd.Timeout)
+	case err != nil:
+		return err
+	}
+
+	var errs []error
+	if exitCode != expectedExitCode {
+		errs = append(errs, fmt.Errorf("exited with code %d, expected %d", exitCode, expectedExitCode))
+	}
+	if err := MatchOutput("stdout", assertCommand.Stdout, stdout.String()); err != nil {
+		if !assertCommand.SkipLogOutput {
+			err = fmt.Errorf("%w\nstdout was:\n%s", err, stdout.String())
+		}
+		errs = append(errs, err)
+	}
+	if err := MatchOutput("stderr", assertCommand.Stderr, stderr.String()); err != nil {
+		if !assertCommand.SkipLogOutput {
+			err = fmt.Errorf("%w\nstderr was:\n%s", err, stderr.String())
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("command %q failed: %w", cmd.String(), errors.Join(errs...))
+	}
+	return nil
 }
 
 // RunCommands runs a set of commands, returning any errors.
 // If any (non-background) command fails, the following commands are skipped
 // commands running in the background are returned.
-func RunCommands(ctx context.Context, logger Logger, namespace string, commands []harness.Command, workdir string, timeout int, kubeconfigOverride string) ([]*exec.Cmd, error) {
+func RunCommands(ctx context.Context, logger Logger, namespace string, commands []harness.Command, workdir string, timeout int, kubeconfigOverride string, vars Variables) ([]*exec.Cmd, error) {
 	bgs := []*exec.Cmd{}
 
 	if commands == nil {
@@ -196,7 +298,7 @@ func RunCommands(ctx context.Context, logger Logger, namespace string, commands
 	}
 
 	for i, cmd := range commands {
-		bg, err := RunCommand(ctx, namespace, cmd, workdir, logger, logger, logger, timeout, kubeconfigOverride)
+		bg, err := RunCommand(ctx, namespace, cmd, workdir, CommandOutput(logger), CommandOutput(logger), logger, timeout, kubeconfigOverride, vars)
 		if err != nil {
 			cmdListSize := len(commands)
 			if i+1 < cmdListSize {
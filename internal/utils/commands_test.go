@@ -2,6 +2,8 @@ package utils //nolint:revive,nolintlint // apparently nolintlint is confused
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -235,7 +237,7 @@ func TestRunScript(t *testing.T) {
 
 			logger := NewTestLogger(t, "")
 			// script runs with output
-			_, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+			_, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 
 			if tt.wantedErr {
 				assert.Error(t, err)
@@ -260,7 +262,7 @@ func TestRunCommand(t *testing.T) {
 
 	logger := NewTestLogger(t, "")
 	// assert foreground cmd returns nil
-	cmd, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.NoError(t, err)
 	assert.Nil(t, cmd)
 	// foreground processes should have stdout
@@ -270,7 +272,7 @@ func TestRunCommand(t *testing.T) {
 	stdout = &bytes.Buffer{}
 
 	// assert background cmd returns process
-	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, cmd)
 
@@ -284,7 +286,7 @@ func TestRunCommand(t *testing.T) {
 	hcmd.Command = "sleep 42"
 
 	// assert foreground cmd times out
-	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 2, "")
+	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 2, "", nil)
 	assert.Error(t, err)
 	assert.True(t, strings.Contains(err.Error(), "timeout"))
 	assert.Nil(t, cmd)
@@ -295,7 +297,7 @@ func TestRunCommand(t *testing.T) {
 	hcmd.Timeout = 2
 
 	// assert foreground cmd times out with command timeout
-	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.Error(t, err)
 	assert.True(t, strings.Contains(err.Error(), "timeout"))
 	assert.Nil(t, cmd)
@@ -311,12 +313,12 @@ func TestRunCommandIgnoreErrors(t *testing.T) {
 
 	logger := NewTestLogger(t, "")
 	// assert foreground cmd returns nil
-	cmd, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.NoError(t, err)
 	assert.Nil(t, cmd)
 
 	hcmd.IgnoreFailure = false
-	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.Error(t, err)
 	assert.Nil(t, cmd)
 
@@ -325,7 +327,7 @@ func TestRunCommandIgnoreErrors(t *testing.T) {
 		Command:       "bad-command",
 		IgnoreFailure: true,
 	}
-	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.Error(t, err)
 	assert.Nil(t, cmd)
 }
@@ -339,7 +341,7 @@ func TestRunCommandSkipLogOutput(t *testing.T) {
 
 	logger := NewTestLogger(t, "")
 	// test there is a stdout
-	cmd, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err := RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.NoError(t, err)
 	assert.Nil(t, cmd)
 	assert.True(t, stdout.Len() > 0)
@@ -348,8 +350,138 @@ func TestRunCommandSkipLogOutput(t *testing.T) {
 	stdout = &bytes.Buffer{}
 	stderr = &bytes.Buffer{}
 	// test there is no stdout
-	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "")
+	cmd, err = RunCommand(t.Context(), "", hcmd, "", stdout, stderr, logger, 0, "", nil)
 	assert.NoError(t, err)
 	assert.Nil(t, cmd)
 	assert.True(t, stdout.Len() == 0)
 }
+
+func TestRunCommandOutputVar(t *testing.T) {
+	logger := NewTestLogger(t, "")
+	vars := Variables{"greeting": "hello"}
+
+	// captured output is trimmed, and variables are visible to later commands
+	_, err := RunCommand(t.Context(), "", harness.Command{Script: "echo \"$greeting world\"", OutputVar: "message"}, "", logger, logger, logger, 0, "", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", vars["message"])
+
+	_, err = RunCommand(t.Context(), "", harness.Command{Command: "echo $message", OutputVar: "copy", SkipLogOutput: true}, "", logger, logger, logger, 0, "", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", vars["copy"])
+
+	// JSONPath extraction
+	hcmd := harness.Command{Script: `echo '{"status": {"ip": "10.0.0.1"}}'`, OutputVar: "ip", OutputJSONPath: "{.status.ip}"}
+	_, err = RunCommand(t.Context(), "", hcmd, "", logger, logger, logger, 0, "", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", vars["ip"])
+
+	hcmd.OutputJSONPath = "{.status.missing}"
+	_, err = RunCommand(t.Context(), "", hcmd, "", logger, logger, logger, 0, "", vars)
+	assert.ErrorContains(t, err, "missing is not found")
+
+	for name, tt := range map[string]struct {
+		cmd  harness.Command
+		vars Variables
+		err  string
+	}{
+		"invalid name":      {cmd: harness.Command{Command: "true", OutputVar: "not-valid"}, vars: vars, err: "invalid variable name"},
+		"background":        {cmd: harness.Command{Command: "true", OutputVar: "foo", Background: true}, vars: vars, err: "background"},
+		"jsonpath only":     {cmd: harness.Command{Command: "true", OutputJSONPath: "{.foo}"}, vars: vars, err: "requires outputVar"},
+		"no variable scope": {cmd: harness.Command{Command: "true", OutputVar: "foo"}, err: "not supported"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := RunCommand(t.Context(), "", tt.cmd, "", logger, logger, logger, 0, "", tt.vars)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestValidateVariableName(t *testing.T) {
+	t.Setenv("KUTTL_TEST_ENV", "set")
+
+	assert.NoError(t, ValidateVariableName("ip"))
+	assert.NoError(t, ValidateVariableName("_IP2"))
+	assert.ErrorContains(t, ValidateVariableName("2ip"), "must match")
+	assert.ErrorContains(t, ValidateVariableName("NAMESPACE"), "set by kuttl")
+	assert.ErrorContains(t, ValidateVariableName("PATH"), "set by kuttl")
+	assert.ErrorContains(t, ValidateVariableName("KUTTL_TEST_ENV"), "override the environment variable")
+}
+
+func TestVariablesEnv(t *testing.T) {
+	assert.Equal(t, map[string]string{"s": "foo", "n": "1", "m": `{"a":"b"}`}, Variables{
+		"s": "foo",
+		"n": 1,
+		"m": map[string]any{"a": "b"},
+	}.Env())
+}
+
+func TestRunAssertCommands(t *testing.T) {
+	logger := NewTestLogger(t, "")
+	exitCode := func(code int) *int { return &code }
+
+	tests := []struct {
+		name     string
+		commands []harness.TestAssertCommand
+		errMsg   string
+		timeout  bool
+	}{
+		{name: "success", commands: []harness.TestAssertCommand{{Command: "true"}}},
+		{name: "failure", commands: []harness.TestAssertCommand{{Command: "false"}}, errMsg: "exited with code 1, expected 0"},
+		{name: "expected exit code", commands: []harness.TestAssertCommand{{Script: "exit 3", ExitCode: exitCode(3)}}},
+		{name: "unexpected success", commands: []harness.TestAssertCommand{{Command: "true", ExitCode: exitCode(3)}}, errMsg: "exited with code 0, expected 3"},
+		{
+			name: "stdout and stderr",
+			commands: []harness.TestAssertCommand{{
+				Script: "echo hello; echo oops >&2",
+				Stdout: &harness.OutputMatcher{Equals: new("hello")},
+				Stderr: &harness.OutputMatcher{Contains: "oops"},
+			}},
+		},
+		{
+			name:     "stdout mismatch",
+			commands: []harness.TestAssertCommand{{Command: "echo hello", Stdout: &harness.OutputMatcher{Equals: new("bye")}}},
+			errMsg:   "stdout was:\nhello",
+		},
+		{
+			name:     "stdout mismatch without log output",
+			commands: []harness.TestAssertCommand{{Command: "echo secret", SkipLogOutput: true, Stdout: &harness.OutputMatcher{Contains: "public"}}},
+			errMsg:   `stdout does not contain "public"`,
+		},
+		{
+			name:     "command timeout",
+			commands: []harness.TestAssertCommand{{Command: "sleep 42", Timeout: 1}},
+			errMsg:   "did not finish within its 1 sec timeout",
+		},
+		{
+			name:     "assert timeout",
+			commands: []harness.TestAssertCommand{{Command: "sleep 42"}},
+			errMsg:   "exceeded 1 sec timeout",
+			timeout:  true,
+		},
+		{
+			name:     "command timeout is limited by the assert timeout",
+			commands: []harness.TestAssertCommand{{Command: "sleep 42", Timeout: 5}},
+			errMsg:   "exceeded 1 sec timeout",
+			timeout:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := 0
+			if tt.timeout {
+				timeout = 1
+			}
+			_, err := RunAssertCommands(t.Context(), logger, "", tt.commands, "", timeout, "", nil)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+			assert.Equal(t, tt.timeout, errors.Is(err, context.DeadlineExceeded))
+			if tt.commands[0].SkipLogOutput {
+				assert.NotContains(t, err.Error(), "stdout was")
+			}
+		})
+	}
+}
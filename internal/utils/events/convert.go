@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// eventsAPIVersions are the versions of the events.k8s.io API to list events from, in turn.
+// They share the same schema, so events of both versions are decoded as events/v1 events.
+var eventsAPIVersions = []string{"events.k8s.io/v1", "events.k8s.io/v1beta1"}
+
+// ListFromAnyAPI returns the events of namespace as core/v1 events.
+// It tries several event APIs in turn: events/v1, events/v1beta1, core/v1.
+func ListFromAnyAPI(ctx context.Context, cl client.Client, namespace string) ([]corev1.Event, error) {
+	var errs []error
+	for _, apiVersion := range eventsAPIVersions {
+		events, err := listEvents(ctx, cl, namespace, apiVersion)
+		if err == nil {
+			return events, nil
+		}
+		errs = append(errs, err)
+	}
+
+	coreList := &corev1.EventList{}
+	if err := cl.List(ctx, coreList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Join(append(errs, err)...)
+	}
+	return coreList.Items, nil
+}
+
+// listEvents returns the events of namespace from apiVersion of the events.k8s.io API as core/v1 events.
+func listEvents(ctx context.Context, cl client.Client, namespace, apiVersion string) ([]corev1.Event, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(apiVersion)
+	list.SetKind("EventList")
+	if err := cl.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("%s: %w", apiVersion, err)
+	}
+
+	events := make([]corev1.Event, 0, len(list.Items))
+	for _, item := range list.Items {
+		var event eventsv1.Event
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &event); err != nil {
+			return nil, fmt.Errorf("%s: decoding event %s: %w", apiVersion, item.GetName(), err)
+		}
+		events = append(events, fromEventsV1(&event))
+	}
+	return events, nil
+}
+
+func fromEventsV1(e *eventsv1.Event) corev1.Event {
+	count := e.DeprecatedCount
+	lastTimestamp := e.DeprecatedLastTimestamp
+	if e.Series != nil {
+		count = e.Series.Count
+		lastTimestamp = metav1.NewTime(e.Series.LastObservedTime.Time)
+	}
+	return corev1.Event{
+		ObjectMeta:          e.ObjectMeta,
+		InvolvedObject:      e.Regarding,
+		Related:             e.Related,
+		Reason:              e.Reason,
+		Message:             e.Note,
+		Type:                e.Type,
+		Action:              e.Action,
+		Count:               count,
+		FirstTimestamp:      e.DeprecatedFirstTimestamp,
+		LastTimestamp:       lastTimestamp,
+		EventTime:           e.EventTime,
+		ReportingController: e.ReportingController,
+		ReportingInstance:   e.ReportingInstance,
+	}
+}
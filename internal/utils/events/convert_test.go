@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestListFromAnyAPI(t *testing.T) {
+	lastSeen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.1", Namespace: "ns"},
+		EventTime:  metav1.NewMicroTime(lastSeen.Add(-time.Minute)),
+		Regarding:  corev1.ObjectReference{Kind: "Pod", Name: "web"},
+		Type:       corev1.EventTypeWarning,
+		Reason:     "BackOff",
+		Note:       "Back-off restarting failed container",
+		Series:     &eventsv1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(lastSeen)},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(event).Build()
+
+	events, err := ListFromAnyAPI(t.Context(), cl, "ns")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "web.1", events[0].Name)
+	assert.Equal(t, corev1.ObjectReference{Kind: "Pod", Name: "web"}, events[0].InvolvedObject)
+	assert.Equal(t, "Back-off restarting failed container", events[0].Message)
+	assert.Equal(t, int32(3), events[0].Count)
+	assert.True(t, events[0].LastTimestamp.Equal(&metav1.Time{Time: lastSeen}))
+}
+
+func TestListFromAnyAPICoreFallback(t *testing.T) {
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.1", Namespace: "ns"},
+		Reason:     "Scheduled",
+	}
+	// The events.k8s.io API is not served.
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(event).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if list.GetObjectKind().GroupVersionKind().Group == eventsv1.GroupName {
+				return errors.New("the server could not find the requested resource")
+			}
+			return cl.List(ctx, list, opts...)
+		},
+	}).Build()
+
+	events, err := ListFromAnyAPI(t.Context(), cl, "ns")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Scheduled", events[0].Reason)
+
+	cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return errors.New("forbidden")
+		},
+	}).Build()
+	_, err = ListFromAnyAPI(t.Context(), cl, "ns")
+	assert.EqualError(t, err, "events.k8s.io/v1: forbidden\nevents.k8s.io/v1beta1: forbidden\nforbidden")
+}
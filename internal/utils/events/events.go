@@ -6,39 +6,11 @@ import (
 	"sort"
 
 	corev1 "k8s.io/api/core/v1"
-	eventsv1 "k8s.io/api/events/v1"
-	eventsbeta1 "k8s.io/api/events/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	testutils "github.com/kudobuilder/kuttl/internal/utils"
 )
 
-// byFirstTimestamp sorts a slice of events by first timestamp, using their involvedObject's name as a tie breaker.
-type byFirstTimestamp []eventsbeta1.Event
-
-func (o byFirstTimestamp) Len() int      { return len(o) }
-func (o byFirstTimestamp) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
-
-func (o byFirstTimestamp) Less(i, j int) bool {
-	if o[i].CreationTimestamp.Equal(&o[j].CreationTimestamp) {
-		return o[i].Name < o[j].Name
-	}
-	return o[i].CreationTimestamp.Before(&o[j].CreationTimestamp)
-}
-
-// byFirstTimestampV1 sorts a slice of eventsv1 by first timestamp, using their involvedObject's name as a tie breaker.
-type byFirstTimestampV1 []eventsv1.Event
-
-func (o byFirstTimestampV1) Len() int      { return len(o) }
-func (o byFirstTimestampV1) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
-
-func (o byFirstTimestampV1) Less(i, j int) bool {
-	if o[i].CreationTimestamp.Equal(&o[j].CreationTimestamp) {
-		return o[i].Name < o[j].Name
-	}
-	return o[i].CreationTimestamp.Before(&o[j].CreationTimestamp)
-}
-
 // byFirstTimestampCoreV1 sorts a slice of corev1 by first timestamp, using their involvedObject's name as a tie breaker.
 type byFirstTimestampCoreV1 []corev1.Event
 
@@ -55,99 +27,15 @@ func (o byFirstTimestampCoreV1) Less(i, j int) bool {
 // CollectAndLog retrieves event resources for a given namespace and logs them with the logger.
 // It tries several event APIs in turn: events/v1, events/v1beta1, core/v1.
 func CollectAndLog(ctx context.Context, cl client.Client, namespace string, caseName string, logger testutils.Logger) {
-	err := collectEventsV1(ctx, cl, namespace, caseName, logger)
-	if err != nil {
-		logger.Log("Trying with events eventsv1beta1 API...")
-		err = collectEventsBeta1(ctx, cl, namespace, caseName, logger)
-		if err != nil {
-			logger.Log("Trying with events corev1 API...")
-			err = collectEventsCoreV1(ctx, cl, namespace, caseName, logger)
-			if err != nil {
-				logger.Log("All event APIs failed")
-			}
-		}
-	}
-}
-
-func collectEventsBeta1(ctx context.Context, cl client.Client, namespace string, caseName string, logger testutils.Logger) error {
-	eventsList := &eventsbeta1.EventList{}
-
-	err := cl.List(ctx, eventsList, client.InNamespace(namespace))
-	if err != nil {
-		logger.Logf("Failed to collect events for %s in ns %s: %v", caseName, namespace, err)
-		return err
-	}
-
-	events := eventsList.Items
-	sort.Sort(byFirstTimestamp(events))
-
-	logger.Logf("%s events from ns %s:", caseName, namespace)
-	printEventsBeta1(events, logger)
-	return nil
-}
-
-func collectEventsV1(ctx context.Context, cl client.Client, namespace string, caseName string, logger testutils.Logger) error {
-	eventsList := &eventsv1.EventList{}
-
-	err := cl.List(ctx, eventsList, client.InNamespace(namespace))
-	if err != nil {
-		logger.Logf("Failed to collect events for %s in ns %s: %v", caseName, namespace, err)
-		return err
-	}
-
-	events := eventsList.Items
-	sort.Sort(byFirstTimestampV1(events))
-
-	logger.Logf("%s events from ns %s:", caseName, namespace)
-	printEventsV1(events, logger)
-	return nil
-}
-
-func collectEventsCoreV1(ctx context.Context, cl client.Client, namespace string, caseName string, logger testutils.Logger) error {
-	eventsList := &corev1.EventList{}
-
-	err := cl.List(ctx, eventsList, client.InNamespace(namespace))
+	events, err := ListFromAnyAPI(ctx, cl, namespace)
 	if err != nil {
-		logger.Logf("Failed to collect events for %s in ns %s: %v", caseName, namespace, err)
-		return err
+		logger.Warnf("Failed to collect events for %s in ns %s: %v", caseName, namespace, err)
+		return
 	}
-
-	events := eventsList.Items
 	sort.Sort(byFirstTimestampCoreV1(events))
 
 	logger.Logf("%s events from ns %s:", caseName, namespace)
 	printEventsCoreV1(events, logger)
-	return nil
-}
-
-func printEventsBeta1(events []eventsbeta1.Event, logger testutils.Logger) {
-	for _, e := range events {
-		// time type regarding action reason note reportingController related
-		logger.Logf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
-			e.CreationTimestamp,
-			e.Type,
-			shortString(&e.Regarding),
-			e.Action,
-			e.Reason,
-			e.Note,
-			e.ReportingController,
-			shortString(e.Related))
-	}
-}
-
-func printEventsV1(events []eventsv1.Event, logger testutils.Logger) {
-	for _, e := range events {
-		// time type regarding action reason note reportingController related
-		logger.Logf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
-			e.CreationTimestamp,
-			e.Type,
-			shortString(&e.Regarding),
-			e.Action,
-			e.Reason,
-			e.Note,
-			e.ReportingController,
-			shortString(e.Related))
-	}
 }
 
 func printEventsCoreV1(events []corev1.Event, logger testutils.Logger) {
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Filter selects events by type, reason and kind of the involved object. Empty lists select everything.
+// Values are compared case-insensitively.
+type Filter struct {
+	Types         []string
+	Reasons       []string
+	InvolvedKinds []string
+}
+
+// Matches reports whether event is selected by the filter.
+func (f Filter) Matches(event *corev1.Event) bool {
+	return matchesAny(f.Types, event.Type) &&
+		matchesAny(f.Reasons, event.Reason) &&
+		matchesAny(f.InvolvedKinds, event.InvolvedObject.Kind)
+}
+
+func matchesAny(values []string, value string) bool {
+	return len(values) == 0 || slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+// Record is an occurrence of an event, tagged with the test step which was running when it was observed.
+type Record struct {
+	// Step is the name of the step which was running.
+	Step string
+	// Observed is the time the occurrence was observed by the recorder.
+	Observed time.Time
+	Event    corev1.Event
+}
+
+// defaultSyncTimeout bounds the wait for the existing events to be listed when the recorder starts, e.g. if listing
+// or watching events is forbidden, or the API server does not respond.
+const defaultSyncTimeout = 30 * time.Second
+
+// Recorder records the events of a namespace as they happen, so that events which expire or are compacted
+// before the end of a test case are not lost. Each occurrence of an event is recorded: a new event, and every
+// update of its count or last observation time.
+type Recorder struct {
+	filter      Filter
+	syncTimeout time.Duration
+
+	mu      sync.Mutex
+	step    string
+	records []Record
+}
+
+// NewRecorder returns a Recorder of the events selected by filter.
+func NewRecorder(filter Filter) *Recorder {
+	return &Recorder{filter: filter, syncTimeout: defaultSyncTimeout}
+}
+
+// Start starts watching the events of namespace until ctx is done. It returns once the existing events are recorded.
+// If they cannot be listed in time, watching is stopped and an error is returned.
+func (r *Recorder) Start(ctx context.Context, cs clientset.Interface, namespace string) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(cs, 0, informers.WithNamespace(namespace))
+	informer := factory.Core().V1().Events().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if event, ok := obj.(*corev1.Event); ok {
+				r.record(event)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			old, ok := oldObj.(*corev1.Event)
+			if !ok {
+				return
+			}
+			if event, ok := newObj.(*corev1.Event); ok && (event.Count != old.Count || !LastSeen(*event).Equal(LastSeen(*old))) {
+				r.record(event)
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	stopAfter := context.AfterFunc(ctx, func() { close(stopCh) })
+	factory.Start(stopCh)
+
+	syncCtx, cancel := context.WithTimeout(ctx, r.syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		if stopAfter() {
+			close(stopCh)
+		}
+		factory.Shutdown()
+		return errors.New("timed out waiting for the events of namespace " + namespace)
+	}
+	return nil
+}
+
+// SetStep sets the name of the step which is running, used to tag the events recorded from now on.
+func (r *Recorder) SetStep(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.step = step
+}
+
+// Records returns the events recorded so far, in the order they were observed.
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.records)
+}
+
+func (r *Recorder) record(event *corev1.Event) {
+	if !r.filter.Matches(event) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, Record{Step: r.step, Observed: time.Now(), Event: *event.DeepCopy()})
+}
+
+// WriteTimeline writes records as a table, with the step each event occurred in.
+func WriteTimeline(w io.Writer, records []Record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "LAST SEEN\tSTEP\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+	for _, record := range records {
+		event := record.Event
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s/%s\t%d\t%s\n", LastSeen(event).UTC().Format(time.RFC3339), record.Step,
+			event.Type, event.Reason, strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, count,
+			strings.TrimSpace(event.Message))
+	}
+	return tw.Flush()
+}
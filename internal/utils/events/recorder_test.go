@@ -0,0 +1,91 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestFilterMatches(t *testing.T) {
+	event := &corev1.Event{
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod"},
+	}
+	assert.True(t, Filter{}.Matches(event))
+	assert.True(t, Filter{Types: []string{"warning"}, InvolvedKinds: []string{"Deployment", "pod"}}.Matches(event))
+	assert.False(t, Filter{Reasons: []string{"Pulled", "Started"}}.Matches(event))
+}
+
+func TestRecorder(t *testing.T) {
+	lastSeen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	existing := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "ns"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+		Type:           corev1.EventTypeNormal,
+		Reason:         "Scheduled",
+		Message:        "Successfully assigned ns/web to node",
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+	cs := fake.NewClientset(existing)
+
+	recorder := NewRecorder(Filter{InvolvedKinds: []string{"Pod"}})
+	recorder.SetStep("setup")
+	require.NoError(t, recorder.Start(t.Context(), cs, "ns"))
+
+	recorder.SetStep("0-deploy")
+	backOff := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.2", Namespace: "ns"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(lastSeen.Add(time.Minute)),
+	}
+	_, err := cs.CoreV1().Events("ns").Create(t.Context(), backOff, metav1.CreateOptions{})
+	require.NoError(t, err)
+	// Filtered out.
+	_, err = cs.CoreV1().Events("ns").Create(t.Context(), &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-rs.1", Namespace: "ns"},
+		InvolvedObject: corev1.ObjectReference{Kind: "ReplicaSet", Name: "web-rs"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(recorder.Records()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	recorder.SetStep("1-check")
+	backOff.Count = 2
+	backOff.LastTimestamp = metav1.NewTime(lastSeen.Add(2 * time.Minute))
+	_, err = cs.CoreV1().Events("ns").Update(t.Context(), backOff, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(recorder.Records()) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTimeline(&buf, recorder.Records()))
+	assert.Equal(t, `LAST SEEN              STEP       TYPE      REASON      OBJECT    COUNT   MESSAGE
+2024-05-01T12:00:00Z   setup      Normal    Scheduled   pod/web   1       Successfully assigned ns/web to node
+2024-05-01T12:01:00Z   0-deploy   Warning   BackOff     pod/web   1       Back-off restarting failed container
+2024-05-01T12:02:00Z   1-check    Warning   BackOff     pod/web   2       Back-off restarting failed container
+`, buf.String())
+}
+
+func TestRecorderSyncTimeout(t *testing.T) {
+	cs := fake.NewClientset()
+	cs.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("events"), "", errors.New("not allowed"))
+	})
+
+	recorder := NewRecorder(Filter{})
+	recorder.syncTimeout = 100 * time.Millisecond
+	assert.EqualError(t, recorder.Start(t.Context(), cs, "ns"), "timed out waiting for the events of namespace ns")
+}
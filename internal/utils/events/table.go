@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// List returns the core/v1 events of namespace, sorted by the time they were last observed.
+func List(ctx context.Context, cl client.Client, namespace string) ([]corev1.Event, error) {
+	events := &corev1.EventList{}
+	if err := cl.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing events in namespace %s: %w", namespace, err)
+	}
+	items := events.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return LastSeen(items[i]).Before(LastSeen(items[j]))
+	})
+	return items, nil
+}
+
+// WriteTable writes events as a table, in the same way as `kubectl get events`.
+func WriteTable(w io.Writer, events []corev1.Event) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for _, event := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s/%s\t%s\n", LastSeen(event).UTC().Format(time.RFC3339), event.Type, event.Reason,
+			strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, strings.TrimSpace(event.Message))
+	}
+	return tw.Flush()
+}
+
+// LastSeen returns the time an event was last observed.
+func LastSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
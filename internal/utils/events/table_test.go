@@ -0,0 +1,44 @@
+package events
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestListAndWriteTable(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := func(name string, lastSeen time.Time, reason, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+			Type:           corev1.EventTypeNormal,
+			Reason:         reason,
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		event("started", start.Add(time.Minute), "Started", "Started container web"),
+		event("pulled", start, "Pulled", "Pulled image\n"),
+	).Build()
+
+	events, err := List(t.Context(), cl, "ns")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "pulled", events[0].Name)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTable(&buf, events))
+	assert.Equal(t, `LAST SEEN              TYPE     REASON    OBJECT    MESSAGE
+2024-05-01T12:00:00Z   Normal   Pulled    pod/web   Pulled image
+2024-05-01T12:01:00Z   Normal   Started   pod/web   Started container web
+`, buf.String())
+}
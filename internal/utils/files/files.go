@@ -32,17 +32,17 @@ func CollectTestStepFiles(dir string, logger testutils.Logger, ignorePatterns []
 	}
 
 	for _, file := range files {
-		if matchesAnyPattern(file.Name(), ignorePatterns) {
+		if matchesAnyPattern(file.Name(), ignorePatterns) || file.Name() == kfile.CaseManifestName {
 			continue
 		}
 
 		f := kfile.Parse(file.Name())
 		if f.Type == kfile.TypeUnknown {
-			logger.Logf("Ignoring %q: %v.", file.Name(), f.Error)
+			logger.Warnf("Ignoring %q: %v.", file.Name(), f.Error)
 			continue
 		}
 		if !f.HasIndex {
-			logger.Logf("Ignoring %q: does not begin with a number followed by a dash.", file.Name())
+			logger.Warnf("Ignoring %q: does not begin with a number followed by a dash.", file.Name())
 			continue
 		}
 
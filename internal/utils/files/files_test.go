@@ -24,6 +24,26 @@ func (m *mockLogger) Logf(format string, args ...interface{}) {
 	m.messages = append(m.messages, fmt.Sprintf(format, args...))
 }
 
+func (m *mockLogger) Errorf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mockLogger) Warnf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mockLogger) Debugf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mockLogger) Tracef(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mockLogger) Suppresses(_ string) bool {
+	return false
+}
+
 func (m *mockLogger) WithPrefix(_ string) testutils.Logger {
 	return m
 }
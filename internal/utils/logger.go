@@ -2,20 +2,105 @@ package utils //nolint:revive,nolintlint // apparently nolintlint is confused
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
 // Logger is an interface used by the KUTTL test operator to provide logging of tests.
 type Logger interface {
+	// Log and Logf log at the info level.
 	Log(args ...interface{})
 	Logf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+	Tracef(format string, args ...interface{})
+	// Suppresses reports whether the logs of category, e.g. LogCommands, are suppressed.
+	Suppresses(category string) bool
 	WithPrefix(prefix string) Logger
 	Write(p []byte) (n int, err error)
 	Flush()
 }
 
+// The categories of logs which can be suppressed.
+const (
+	// LogEvents are the events of the test namespace.
+	LogEvents = "events"
+	// LogCommands is the output of commands.
+	LogCommands = "commands"
+	// LogDiffs are the diffs between the expected and actual resources of failed assertions.
+	LogDiffs = "diffs"
+	// LogResources are the messages about the resources applied and deleted by test steps.
+	LogResources = "resources"
+)
+
+// Level is the severity of a log record. Loggers log the records up to their level.
+type Level int
+
+// The levels, from the most to the least severe.
+const (
+	LevelError Level = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+	LevelTrace
+)
+
+var levelNames = []string{"error", "warn", "info", "debug", "trace"}
+
+func (l Level) String() string {
+	if l < LevelError || l > LevelTrace {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level named by level. An empty level is the info level.
+func ParseLevel(level string) (Level, error) {
+	if level == "" {
+		return LevelInfo, nil
+	}
+	i := slices.Index(levelNames, strings.ToLower(level))
+	if i < 0 {
+		return 0, fmt.Errorf("unrecognized log level %q, must be one of %s", level, strings.Join(levelNames, ", "))
+	}
+	return Level(i), nil
+}
+
+// LevelForVerbosity returns the Level for a -v verbosity: 0 is info, 1 is debug, 2 and above is trace.
+func LevelForVerbosity(verbosity int) Level {
+	return min(LevelInfo+Level(max(verbosity, 0)), LevelTrace)
+}
+
+// LogFormat is the format of the records logged by a TestLogger.
+type LogFormat string
+
+const (
+	// LogFormatText logs records as text through the go test operator, prefixed with the time and logger prefix.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON logs records as JSON lines, written directly to the output as they are logged.
+	LogFormatJSON LogFormat = "json"
+)
+
+// ParseLogFormat returns the LogFormat named by format. An empty format is the text format.
+func ParseLogFormat(format string) (LogFormat, error) {
+	switch LogFormat(strings.ToLower(format)) {
+	case "", LogFormatText:
+		return LogFormatText, nil
+	case LogFormatJSON:
+		return LogFormatJSON, nil
+	default:
+		return "", fmt.Errorf("unrecognized log format %q, must be one of text or json", format)
+	}
+}
+
 // TestLogger implements the Logger interface to be compatible with the go test operator's
 // output buffering (without this, the use of Parallel tests combined with subtests causes test
 // output to be mixed).
@@ -23,33 +108,156 @@ type TestLogger struct {
 	prefix string
 	test   *testing.T
 	buffer []byte
+	format LogFormat
+	level  Level
+	// Categories of logs which are suppressed.
+	suppressions []string
+	// Output of the records in the JSON format.
+	out io.Writer
+}
+
+// TestLoggerOption represents a functional option for configuring a TestLogger.
+type TestLoggerOption func(*TestLogger)
+
+// WithLogFormat sets the format of the records.
+func WithLogFormat(format LogFormat) TestLoggerOption {
+	return func(t *TestLogger) {
+		t.format = format
+	}
+}
+
+// WithLogLevel sets the level up to which records are logged.
+func WithLogLevel(level Level) TestLoggerOption {
+	return func(t *TestLogger) {
+		t.level = level
+	}
+}
+
+// WithSuppressions sets the categories of logs which are suppressed.
+func WithSuppressions(categories []string) TestLoggerOption {
+	return func(t *TestLogger) {
+		t.suppressions = categories
+	}
+}
+
+// WithLogOutput sets the output of the records in the JSON format, which defaults to the standard output.
+func WithLogOutput(out io.Writer) TestLoggerOption {
+	return func(t *TestLogger) {
+		t.out = out
+	}
 }
 
 // NewTestLogger creates a new test logger.
-func NewTestLogger(test *testing.T, prefix string) *TestLogger {
-	return &TestLogger{
+func NewTestLogger(test *testing.T, prefix string, options ...TestLoggerOption) *TestLogger {
+	t := &TestLogger{
 		prefix: prefix,
 		test:   test,
 		buffer: []byte{},
+		format: LogFormatText,
+		level:  LevelInfo,
+		out:    os.Stdout,
+	}
+	for _, option := range options {
+		option(t)
 	}
+	return t
 }
 
 // Log logs the provided arguments with the logger's prefix. See testing.Log for more details.
 func (t *TestLogger) Log(args ...interface{}) {
-	args = append([]interface{}{
-		fmt.Sprintf("%s | %s |", time.Now().Format("15:04:05"), t.prefix),
-	}, args...)
-	t.test.Log(args...)
+	t.log(LevelInfo, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
 }
 
 // Logf logs the provided arguments with the logger's prefix. See testing.Logf for more details.
 func (t *TestLogger) Logf(format string, args ...interface{}) {
-	t.Log(fmt.Sprintf(format, args...))
+	t.log(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs at the error level. It does not fail the test.
+func (t *TestLogger) Errorf(format string, args ...interface{}) {
+	t.log(LevelError, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs at the warn level.
+func (t *TestLogger) Warnf(format string, args ...interface{}) {
+	t.log(LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Debugf logs at the debug level.
+func (t *TestLogger) Debugf(format string, args ...interface{}) {
+	t.log(LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Tracef logs at the trace level.
+func (t *TestLogger) Tracef(format string, args ...interface{}) {
+	t.log(LevelTrace, fmt.Sprintf(format, args...))
+}
+
+// Suppresses reports whether the logs of category are suppressed.
+func (t *TestLogger) Suppresses(category string) bool {
+	return slices.ContainsFunc(t.suppressions, func(s string) bool {
+		return strings.EqualFold(s, category)
+	})
+}
+
+// log logs msg if level is enabled. In the text format, records of levels other than info are tagged with their level.
+func (t *TestLogger) log(level Level, msg string) {
+	if level > t.level {
+		return
+	}
+	if t.format == LogFormatJSON {
+		t.logJSON(level, msg)
+		return
+	}
+	prefix := fmt.Sprintf("%s | %s |", time.Now().Format("15:04:05"), t.prefix)
+	if level != LevelInfo {
+		prefix = fmt.Sprintf("%s %s |", prefix, strings.ToUpper(level.String()))
+	}
+	t.test.Log(prefix, msg)
 }
 
 // WithPrefix returns a new TestLogger with the provided prefix appended to the current prefix.
 func (t *TestLogger) WithPrefix(prefix string) Logger {
-	return NewTestLogger(t.test, fmt.Sprintf("%s/%s", t.prefix, prefix))
+	return NewTestLogger(t.test, fmt.Sprintf("%s/%s", t.prefix, prefix), WithLogFormat(t.format), WithLogLevel(t.level),
+		WithSuppressions(t.suppressions), WithLogOutput(t.out))
+}
+
+// jsonRecord is a record logged in the JSON format.
+type jsonRecord struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Case    string    `json:"case,omitempty"`
+	Step    string    `json:"step,omitempty"`
+	Message string    `json:"msg"`
+}
+
+// jsonOutputLock serializes the records written by all the loggers, which may share their output.
+var jsonOutputLock sync.Mutex
+
+// logJSON writes msg as a JSON record. The prefix of the loggers of test cases is the name of the test case,
+// followed by the name of the step for the loggers of test steps. If the record cannot be written, msg is logged
+// to the test instead.
+func (t *TestLogger) logJSON(level Level, msg string) {
+	caseName, stepName, _ := strings.Cut(t.prefix, "/")
+	line, err := json.Marshal(jsonRecord{
+		Time:    time.Now(),
+		Level:   level.String(),
+		Case:    caseName,
+		Step:    stepName,
+		Message: msg,
+	})
+	if err != nil {
+		t.test.Log(msg)
+		return
+	}
+
+	jsonOutputLock.Lock()
+	_, err = t.out.Write(append(line, '\n'))
+	jsonOutputLock.Unlock()
+	if err != nil {
+		t.test.Logf("failed to write log record: %v", err)
+		t.test.Log(msg)
+	}
 }
 
 // Write implements the io.Writer interface.
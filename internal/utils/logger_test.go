@@ -0,0 +1,105 @@
+package utils //nolint:revive,nolintlint // apparently nolintlint is confused
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogFormat(t *testing.T) {
+	for format, expected := range map[string]LogFormat{
+		"":     LogFormatText,
+		"text": LogFormatText,
+		"JSON": LogFormatJSON,
+	} {
+		actual, err := ParseLogFormat(format)
+		require.NoError(t, err, format)
+		assert.Equal(t, expected, actual, format)
+	}
+
+	_, err := ParseLogFormat("yaml")
+	assert.EqualError(t, err, `unrecognized log format "yaml", must be one of text or json`)
+}
+
+func TestTestLoggerJSON(t *testing.T) {
+	var out bytes.Buffer
+	caseLogger := NewTestLogger(t, "my-test", WithLogFormat(LogFormatJSON), WithLogOutput(&out))
+	caseLogger.Log("starting", "test")
+	stepLogger := caseLogger.WithPrefix("1-install")
+	stepLogger.Logf("applying %d objects", 2)
+	fmt.Fprint(stepLogger, "line one\nline ")
+	fmt.Fprint(stepLogger, "two")
+	stepLogger.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	var records []map[string]any
+	for _, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		assert.Equal(t, "info", record["level"])
+		assert.NotEmpty(t, record["time"])
+		records = append(records, record)
+	}
+
+	assert.Equal(t, "my-test", records[0]["case"])
+	assert.NotContains(t, records[0], "step")
+	assert.Equal(t, "starting test", records[0]["msg"])
+	assert.Equal(t, "my-test", records[1]["case"])
+	assert.Equal(t, "1-install", records[1]["step"])
+	assert.Equal(t, "applying 2 objects", records[1]["msg"])
+	assert.Equal(t, "line one", records[2]["msg"])
+	assert.Equal(t, "line two", records[3]["msg"])
+}
+
+func TestParseLevel(t *testing.T) {
+	for level, expected := range map[string]Level{
+		"":      LevelInfo,
+		"error": LevelError,
+		"Warn":  LevelWarn,
+		"trace": LevelTrace,
+	} {
+		actual, err := ParseLevel(level)
+		require.NoError(t, err, level)
+		assert.Equal(t, expected, actual, level)
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.EqualError(t, err, `unrecognized log level "verbose", must be one of error, warn, info, debug, trace`)
+}
+
+func TestLevelForVerbosity(t *testing.T) {
+	assert.Equal(t, LevelInfo, LevelForVerbosity(0))
+	assert.Equal(t, LevelDebug, LevelForVerbosity(1))
+	assert.Equal(t, LevelTrace, LevelForVerbosity(2))
+	assert.Equal(t, LevelTrace, LevelForVerbosity(5))
+}
+
+func TestTestLoggerLevels(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewTestLogger(t, "my-test", WithLogFormat(LogFormatJSON), WithLogOutput(&out), WithLogLevel(LevelDebug),
+		WithSuppressions([]string{"Commands"})).WithPrefix("0-setup")
+	logger.Errorf("error %d", 1)
+	logger.Warnf("warn")
+	logger.Log("info")
+	logger.Debugf("debug")
+	logger.Tracef("trace")
+
+	var levels, messages []any
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		levels = append(levels, record["level"])
+		messages = append(messages, record["msg"])
+	}
+	assert.Equal(t, []any{"error", "warn", "info", "debug"}, levels)
+	assert.Equal(t, []any{"error 1", "warn", "info", "debug"}, messages)
+
+	assert.True(t, logger.Suppresses(LogCommands))
+	assert.False(t, logger.Suppresses(LogDiffs))
+}
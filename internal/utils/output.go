@@ -0,0 +1,79 @@
+package utils //nolint:revive,nolintlint // apparently nolintlint is confused
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+// MatchOutput checks the output of a command against all the expectations set in matcher.
+// stream is the name of the output (e.g. stdout) used in error messages.
+func MatchOutput(stream string, matcher *harness.OutputMatcher, output string) error {
+	if matcher == nil {
+		return nil
+	}
+
+	var errs []error
+	if matcher.Contains != "" && !strings.Contains(output, matcher.Contains) {
+		errs = append(errs, fmt.Errorf("%s does not contain %q", stream, matcher.Contains))
+	}
+	if matcher.Equals != nil {
+		if err := matchEquals(stream, *matcher.Equals, output); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if matcher.Regex != "" {
+		re, err := regexp.Compile(matcher.Regex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s regex %q: %w", stream, matcher.Regex, err))
+		} else if !re.MatchString(output) {
+			errs = append(errs, fmt.Errorf("%s does not match regex %q", stream, matcher.Regex))
+		}
+	}
+	if matcher.Subset != "" {
+		if err := matchSubset(stream, matcher.Subset, output); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func matchEquals(stream, expected, output string) error {
+	expected = strings.TrimSpace(expected)
+	actual := strings.TrimSpace(output)
+	if expected == actual {
+		return nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected + "\n"),
+		B:        difflib.SplitLines(actual + "\n"),
+		FromFile: "expected",
+		ToFile:   stream,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("%s is not equal to the expected value", stream)
+	}
+	return fmt.Errorf("%s is not equal to the expected value:\n%s", stream, diff)
+}
+
+func matchSubset(stream, expectedDoc, output string) error {
+	var expected, actual any
+	if err := yaml.Unmarshal([]byte(expectedDoc), &expected); err != nil {
+		return fmt.Errorf("invalid expected %s document: %w", stream, err)
+	}
+	if err := yaml.Unmarshal([]byte(output), &actual); err != nil {
+		return fmt.Errorf("%s is not a JSON or YAML document: %w", stream, err)
+	}
+	if err := IsSubset(expected, actual); err != nil {
+		return fmt.Errorf("%s does not contain the expected document: %w", stream, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package utils //nolint:revive,nolintlint // apparently nolintlint is confused
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	harness "github.com/kudobuilder/kuttl/pkg/apis/testharness/v1beta1"
+)
+
+func TestMatchOutput(t *testing.T) {
+	const output = `{"status": {"phase": "Running", "ready": true}, "items": [1, 2]}
+`
+	tests := []struct {
+		name    string
+		matcher *harness.OutputMatcher
+		errMsg  string
+	}{
+		{name: "nil matcher"},
+		{name: "contains", matcher: &harness.OutputMatcher{Contains: `"Running"`}},
+		{name: "does not contain", matcher: &harness.OutputMatcher{Contains: "Pending"}, errMsg: `stdout does not contain "Pending"`},
+		{name: "equals ignoring whitespace", matcher: &harness.OutputMatcher{Equals: new(output[:len(output)-1] + "  ")}},
+		{name: "not equal", matcher: &harness.OutputMatcher{Equals: new("foo")}, errMsg: "+++ stdout"},
+		{name: "not empty", matcher: &harness.OutputMatcher{Equals: new("")}, errMsg: "stdout is not equal to the expected value"},
+		{name: "regex", matcher: &harness.OutputMatcher{Regex: `"phase": "(Running|Succeeded)"`}},
+		{name: "regex mismatch", matcher: &harness.OutputMatcher{Regex: `^Pending`}, errMsg: `stdout does not match regex "^Pending"`},
+		{name: "invalid regex", matcher: &harness.OutputMatcher{Regex: `(`}, errMsg: "invalid stdout regex"},
+		{name: "subset", matcher: &harness.OutputMatcher{Subset: "status:\n  phase: Running\n"}},
+		{name: "subset with list", matcher: &harness.OutputMatcher{Subset: "items: [1, 2]"}},
+		{name: "subset mismatch", matcher: &harness.OutputMatcher{Subset: "status:\n  ready: false\n"}, errMsg: ".status.ready: value mismatch"},
+		{name: "all must match", matcher: &harness.OutputMatcher{Contains: "Running", Regex: "Pending"}, errMsg: "does not match regex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MatchOutput("stdout", tt.matcher, output)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestMatchOutputEmpty(t *testing.T) {
+	assert.NoError(t, MatchOutput("stdout", &harness.OutputMatcher{Equals: new("")}, " \n"))
+}
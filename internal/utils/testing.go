@@ -17,6 +17,11 @@ import (
 // If testToRun is set to a non-empty string, it is passed as a `-run` argument to the go test harness.
 // If paralellism is set, it limits the number of concurrently running tests.
 func RunTests(testName string, testToRun string, parallelism int, testFunc func(*testing.T)) {
+	os.Exit(MainStart(testName, testToRun, parallelism, testFunc).Run())
+}
+
+// MainStart prepares the tests in the same way as RunTests, returning them to be run by the caller.
+func MainStart(testName string, testToRun string, parallelism int, testFunc func(*testing.T)) *testing.M {
 	flag.Parse()
 	testing.Init()
 
@@ -41,12 +46,12 @@ func RunTests(testName string, testToRun string, parallelism int, testFunc func(
 		panic(err)
 	}
 
-	os.Exit(testing.MainStart(&testDeps{}, []testing.InternalTest{
+	return testing.MainStart(&testDeps{}, []testing.InternalTest{
 		{
 			Name: testName,
 			F:    testFunc,
 		},
-	}, nil, nil, nil).Run())
+	}, nil, nil, nil)
 }
 
 // testDeps implements the testDeps interface for MainStart.
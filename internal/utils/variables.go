@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var variableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// commandEnvNames are the environment variables kuttl sets for commands.
+var commandEnvNames = []string{"NAMESPACE", "KUBECONFIG", "PATH"}
+
+// Variables holds the values captured during a test case, keyed by variable name.
+// They are made available to the templates, commands and assertions of the following steps.
+// Only the captured values are exported to commands, the template variables are not.
+type Variables map[string]any
+
+// ValidateVariableName checks that name can be used both as a template field and as an environment variable name.
+// Since variables are exported to commands, name must not be the name of an environment variable of kuttl,
+// or of one kuttl sets for commands, which the variable would silently replace.
+func ValidateVariableName(name string) error {
+	if !variableNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q, must match %s", name, variableNameRegexp)
+	}
+	if slices.Contains(commandEnvNames, name) {
+		return fmt.Errorf("invalid variable name %q, it is set by kuttl for commands", name)
+	}
+	if _, ok := os.LookupEnv(name); ok {
+		return fmt.Errorf("invalid variable name %q, it would override the environment variable of the same name", name)
+	}
+	return nil
+}
+
+// Env returns the variables as environment variables.
+// String values are used verbatim, other values are JSON-encoded.
+func (v Variables) Env() map[string]string {
+	env := make(map[string]string, len(v))
+	for name, value := range v {
+		if s, ok := value.(string); ok {
+			env[name] = s
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		env[name] = string(encoded)
+	}
+	return env
+}
+
+// EvalJSONPath evaluates a kubectl-style JSONPath expression, e.g. `{.spec.clusterIP}`, against data.
+// The result is formatted the same way as by `kubectl get -o jsonpath=...`.
+func EvalJSONPath(expr string, data any) (string, error) {
+	j := jsonpath.New("").AllowMissingKeys(false)
+	if err := j.Parse(expr); err != nil {
+		return "", fmt.Errorf("parsing JSONPath %q: %w", expr, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := j.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("evaluating JSONPath %q: %w", expr, err)
+	}
+	return buf.String(), nil
+}
+
+// extractOutput returns the value to store from the standard output of a command.
+// Without a JSONPath expression, the output with surrounding whitespace trimmed is returned.
+// Otherwise the output is parsed as JSON or YAML and the expression is evaluated against it.
+func extractOutput(output []byte, jsonPath string) (string, error) {
+	if jsonPath == "" {
+		return strings.TrimSpace(string(output)), nil
+	}
+	var data any
+	if err := yaml.Unmarshal(output, &data); err != nil {
+		return "", fmt.Errorf("parsing command output: %w", err)
+	}
+	return EvalJSONPath(jsonPath, data)
+}
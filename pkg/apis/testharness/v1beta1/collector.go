@@ -6,38 +6,73 @@ import (
 	"strings"
 )
 
+// Types of TestCollector.
 const (
-	pod     = "pod"
-	events  = "events"
-	command = "command"
+	CollectorTypePod       = "pod"
+	CollectorTypeEvents    = "events"
+	CollectorTypeCommand   = "command"
+	CollectorTypeResources = "resources"
 )
 
-// validate checks user input and updates type if not provided.
-// It is expected to be called prior to any other call.
-func (tc *TestCollector) validate() error {
+// Outcomes of a test step after which a TestCollector runs.
+const (
+	CollectorWhenFailure = "failure"
+	CollectorWhenSuccess = "success"
+	CollectorWhenAlways  = "always"
+)
+
+// Validate checks user input and sets the type if not provided.
+func (tc *TestCollector) Validate() error {
 	cleanType(tc)
+	return tc.validate()
+}
+
+// withDefaults returns a copy of the collector with its type and when set, and validates it.
+// It does not modify the collector, which may be shared by the test cases running in parallel.
+func (tc *TestCollector) withDefaults() (TestCollector, error) {
+	c := *tc
+	cleanType(&c)
+	return c, c.validate()
+}
+
+// validate checks user input, once cleanType set the type and when.
+func (tc *TestCollector) validate() error {
+	switch tc.When {
+	case CollectorWhenFailure, CollectorWhenSuccess, CollectorWhenAlways:
+	default:
+		return fmt.Errorf("collector when %q unknown, must be one of failure, success or always", tc.When)
+	}
 	switch tc.Type {
-	case command:
+	case CollectorTypeCommand:
 		return validateCmd(tc)
-	case pod:
+	case CollectorTypePod:
 		return validPod(tc)
-	case events:
+	case CollectorTypeEvents:
 		return validEvents(tc)
+	case CollectorTypeResources:
+		return validResources(tc)
 	default:
 		return fmt.Errorf("collector type %q unknown", tc.Type)
 	}
 }
 
 func validEvents(tc *TestCollector) error {
-	if tc.Cmd != "" || tc.Selector != "" || tc.Container != "" {
-		return errors.New("event collector can not have a selector, container or command")
+	if tc.Cmd != "" || tc.Selector != "" || tc.Container != "" || len(tc.Kinds) > 0 {
+		return errors.New("event collector can not have a selector, container, command or kinds")
+	}
+	return nil
+}
+
+func validResources(tc *TestCollector) error {
+	if tc.Cmd != "" || tc.Pod != "" || tc.Container != "" {
+		return errors.New("resources collector can not have a pod, container or command")
 	}
 	return nil
 }
 
 func validPod(tc *TestCollector) error {
-	if tc.Cmd != "" {
-		return errors.New("pod collector can NOT have a command")
+	if tc.Cmd != "" || len(tc.Kinds) > 0 {
+		return errors.New("pod collector can NOT have a command or kinds")
 	}
 	if tc.Pod == "" && tc.Selector == "" {
 		return errors.New("pod collector requires a pod or selector")
@@ -49,8 +84,8 @@ func validateCmd(tc *TestCollector) error {
 	if tc.Cmd == "" {
 		return errors.New("command collector requires a command")
 	}
-	if tc.Pod != "" || tc.Namespace != "" || tc.Container != "" || tc.Selector != "" {
-		return errors.New("command collectors can NOT have pod, namespace, container or selectors")
+	if tc.Pod != "" || tc.Namespace != "" || tc.Container != "" || tc.Selector != "" || len(tc.Kinds) > 0 {
+		return errors.New("command collectors can NOT have pod, namespace, container, selectors or kinds")
 	}
 	return nil
 }
@@ -61,30 +96,49 @@ func cleanType(tc *TestCollector) {
 	if tc.Type == "" {
 		// assume command if cmd provided
 		if tc.Cmd != "" {
-			tc.Type = command
+			tc.Type = CollectorTypeCommand
 		} else {
-			tc.Type = pod
+			tc.Type = CollectorTypePod
 		}
 	}
 	tc.Type = strings.ToLower(tc.Type)
+	if tc.When == "" {
+		tc.When = CollectorWhenFailure
+	}
+	tc.When = strings.ToLower(tc.When)
+}
+
+// RunsAfter reports whether the collector runs after a step which failed, or succeeded if failed is false.
+func (tc *TestCollector) RunsAfter(failed bool) bool {
+	switch tc.When {
+	case CollectorWhenAlways:
+		return true
+	case CollectorWhenSuccess:
+		return !failed
+	default:
+		return failed
+	}
 }
 
 // Command provides the command to exec to perform the collection.
+// It returns nil for invalid collectors and for the resources type, which has no equivalent command.
+// kuttl only runs it for the command type, and collects pod logs and events with the API instead. The kubectl
+// commands of those types are kept for the users of this package, as the equivalent of what kuttl collects.
 func (tc *TestCollector) Command() *Command {
-	err := tc.validate()
+	c, err := tc.withDefaults()
 	if err != nil {
 		return nil
 	}
-	switch tc.Type {
-	case pod:
-		return podCommand(tc)
-	case command:
+	switch c.Type {
+	case CollectorTypePod:
+		return podCommand(&c)
+	case CollectorTypeCommand:
 		return &Command{
-			Command:       tc.Cmd,
+			Command:       c.Cmd,
 			IgnoreFailure: true,
 		}
-	case events:
-		return eventCommand(tc)
+	case CollectorTypeEvents:
+		return eventCommand(&c)
 	}
 	return nil
 }
@@ -125,23 +179,29 @@ func podCommand(tc *TestCollector) *Command {
 	} else {
 		b.WriteString(" --all-containers")
 	}
-	if tc.Tail == 0 {
-		if len(tc.Selector) > 0 {
-			tc.Tail = 10
-		} else {
-			tc.Tail = -1
-		}
-	}
-	fmt.Fprintf(&b, " --tail=%d", tc.Tail)
+	fmt.Fprintf(&b, " --tail=%d", tc.TailLines())
 	return &Command{
 		Command:       b.String(),
 		IgnoreFailure: true,
 	}
 }
 
+// TailLines returns the number of last lines to collect from pods, applying the defaults of `kubectl logs`:
+// 10 when using a selector, or -1 (all) when using a pod name.
+func (tc *TestCollector) TailLines() int {
+	switch {
+	case tc.Tail != 0:
+		return tc.Tail
+	case len(tc.Selector) > 0:
+		return 10
+	default:
+		return -1
+	}
+}
+
 // String provides defaults of the type of collector.
 func (tc *TestCollector) String() string {
-	err := tc.validate()
+	c, err := tc.withDefaults()
 	if err != nil {
 		return fmt.Sprintf("[collector invalid: %s]", err.Error())
 	}
@@ -149,21 +209,27 @@ func (tc *TestCollector) String() string {
 	var b strings.Builder
 	b.WriteString("[")
 	details := []string{}
-	details = append(details, fmt.Sprintf("type==%s", tc.Type))
-	if len(tc.Pod) > 0 {
-		details = append(details, fmt.Sprintf("pod==%s", tc.Pod))
+	details = append(details, fmt.Sprintf("type==%s", c.Type))
+	if len(c.Pod) > 0 {
+		details = append(details, fmt.Sprintf("pod==%s", c.Pod))
 	}
-	if len(tc.Selector) > 0 {
-		details = append(details, fmt.Sprintf("label: %s", tc.Selector))
+	if len(c.Selector) > 0 {
+		details = append(details, fmt.Sprintf("label: %s", c.Selector))
 	}
-	if len(tc.Namespace) > 0 {
-		details = append(details, fmt.Sprintf("namespace: %s", tc.Namespace))
+	if len(c.Namespace) > 0 {
+		details = append(details, fmt.Sprintf("namespace: %s", c.Namespace))
 	}
-	if len(tc.Container) > 0 {
-		details = append(details, fmt.Sprintf("container: %s", tc.Container))
+	if len(c.Container) > 0 {
+		details = append(details, fmt.Sprintf("container: %s", c.Container))
+	}
+	if len(c.Cmd) > 0 {
+		details = append(details, fmt.Sprintf("command: %s", c.Cmd))
+	}
+	if len(c.Kinds) > 0 {
+		details = append(details, fmt.Sprintf("kinds: %s", strings.Join(c.Kinds, " ")))
 	}
-	if len(tc.Cmd) > 0 {
-		details = append(details, fmt.Sprintf("command: %s", tc.Cmd))
+	if c.When != CollectorWhenFailure {
+		details = append(details, fmt.Sprintf("when: %s", c.When))
 	}
 	b.WriteString(strings.Join(details, ","))
 	b.WriteString("]")
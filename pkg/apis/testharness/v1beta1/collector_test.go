@@ -15,6 +15,7 @@ func TestTestCollector_String(t *testing.T) {
 		Container string
 		Selector  string
 		Cmd       string
+		Kinds     []string
 	}
 	tests := []struct {
 		name     string
@@ -101,6 +102,26 @@ func TestTestCollector_String(t *testing.T) {
 			fields:   fields{Type: "command", Pod: "foo"},
 			contains: "collector invalid:",
 		},
+		{
+			name:     "valid resources",
+			fields:   fields{Type: "resources", Selector: "app=foo", Kinds: []string{"deployments.apps", "pods"}},
+			contains: "kinds: deployments.apps pods",
+		},
+		{
+			name:     "valid resources without kinds",
+			fields:   fields{Type: "resources"},
+			contains: "type==resources",
+		},
+		{
+			name:     "invalid resources with pod",
+			fields:   fields{Type: "resources", Pod: "foo"},
+			contains: "collector invalid:",
+		},
+		{
+			name:     "invalid pod with kinds",
+			fields:   fields{Pod: "foo", Kinds: []string{"pods"}},
+			contains: "collector invalid:",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -111,6 +132,7 @@ func TestTestCollector_String(t *testing.T) {
 				Container: tt.fields.Container,
 				Selector:  tt.fields.Selector,
 				Cmd:       tt.fields.Cmd,
+				Kinds:     tt.fields.Kinds,
 			}
 			got := tc.String()
 			if !strings.Contains(got, tt.contains) {
@@ -128,22 +150,22 @@ func TestPodCommand(t *testing.T) {
 	}{
 		{
 			name: "selector with default tail",
-			tc:   TestCollector{Type: pod, Selector: "x=y"},
+			tc:   TestCollector{Type: CollectorTypePod, Selector: "x=y"},
 			cmd:  "kubectl logs --prefix -l x=y -n $NAMESPACE --all-containers --tail=10",
 		},
 		{
 			name: "pod name with default tail",
-			tc:   TestCollector{Type: pod, Pod: "foo"},
+			tc:   TestCollector{Type: CollectorTypePod, Pod: "foo"},
 			cmd:  "kubectl logs --prefix foo -n $NAMESPACE --all-containers --tail=-1",
 		},
 		{
 			name: "selector with set tail",
-			tc:   TestCollector{Type: pod, Selector: "x=y", Tail: 42},
+			tc:   TestCollector{Type: CollectorTypePod, Selector: "x=y", Tail: 42},
 			cmd:  "kubectl logs --prefix -l x=y -n $NAMESPACE --all-containers --tail=42",
 		},
 		{
 			name: "pod name with set tail",
-			tc:   TestCollector{Type: pod, Pod: "foo", Tail: 42},
+			tc:   TestCollector{Type: CollectorTypePod, Pod: "foo", Tail: 42},
 			cmd:  "kubectl logs --prefix foo -n $NAMESPACE --all-containers --tail=42",
 		},
 	}
@@ -154,3 +176,27 @@ func TestPodCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestTestCollector_TailLines(t *testing.T) {
+	assert.Equal(t, -1, (&TestCollector{Pod: "foo"}).TailLines())
+	assert.Equal(t, 10, (&TestCollector{Selector: "app=foo"}).TailLines())
+	assert.Equal(t, 42, (&TestCollector{Selector: "app=foo", Tail: 42}).TailLines())
+}
+
+func TestTestCollector_RunsAfter(t *testing.T) {
+	for when, expected := range map[string][2]bool{
+		"":        {true, false},
+		"failure": {true, false},
+		"Success": {false, true},
+		"always":  {true, true},
+	} {
+		tc := &TestCollector{Pod: "foo", When: when}
+		assert.NoError(t, tc.Validate(), when)
+		assert.Equal(t, expected[0], tc.RunsAfter(true), when)
+		assert.Equal(t, expected[1], tc.RunsAfter(false), when)
+	}
+
+	tc := &TestCollector{Pod: "foo", When: "timeout"}
+	assert.ErrorContains(t, tc.Validate(), `collector when "timeout" unknown`)
+	assert.Contains(t, (&TestCollector{Type: "events", When: "always"}).String(), "when: always")
+}
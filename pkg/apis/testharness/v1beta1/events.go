@@ -0,0 +1,52 @@
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Validate checks that at least one property of the events is specified, and that the message is a valid regex.
+func (e *TestEvent) Validate() error {
+	if e.InvolvedObject == (TestEventObject{}) && e.Type == "" && e.Reason == "" && e.Message == "" {
+		return errors.New("at least one of involvedObject, type, reason and message must be specified")
+	}
+	if e.MinCount < 0 {
+		return fmt.Errorf("minCount must not be negative, got %d", e.MinCount)
+	}
+	if e.Message != "" {
+		if _, err := regexp.Compile(e.Message); err != nil {
+			return fmt.Errorf("invalid message regex %q: %w", e.Message, err)
+		}
+	}
+	return nil
+}
+
+func (e *TestEvent) String() string {
+	var b strings.Builder
+	b.WriteString("event")
+	if e.Type != "" {
+		fmt.Fprintf(&b, " %s", e.Type)
+	}
+	if e.Reason != "" {
+		fmt.Fprintf(&b, " %s", e.Reason)
+	}
+	if o := e.InvolvedObject; o != (TestEventObject{}) {
+		kind := o.Kind
+		if kind == "" {
+			kind = "object"
+		}
+		fmt.Fprintf(&b, " about %s", kind)
+		if o.Name != "" {
+			fmt.Fprintf(&b, " %s", o.Name)
+		}
+		if o.APIVersion != "" {
+			fmt.Fprintf(&b, " (%s)", o.APIVersion)
+		}
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, " with message matching %q", e.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestEventValidate(t *testing.T) {
+	for name, tt := range map[string]struct {
+		event  TestEvent
+		errMsg string
+	}{
+		"reason":            {event: TestEvent{Type: "Warning", Reason: "ReconcileFailed"}},
+		"involved object":   {event: TestEvent{InvolvedObject: TestEventObject{Kind: "Deployment", Name: "web"}, MinCount: 2}},
+		"message":           {event: TestEvent{Message: `^Scaled up replica set web-\w+ to 3$`}},
+		"no property":       {event: TestEvent{Namespace: "default"}, errMsg: "at least one of involvedObject, type, reason and message"},
+		"negative minCount": {event: TestEvent{Reason: "Started", MinCount: -1}, errMsg: "minCount must not be negative"},
+		"invalid regex":     {event: TestEvent{Message: "("}, errMsg: `invalid message regex "("`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tt.event.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTestEventString(t *testing.T) {
+	e := TestEvent{Type: "Warning", Reason: "ReconcileFailed", InvolvedObject: TestEventObject{Kind: "MyApp", Name: "web"}, Message: "quota"}
+	assert.Equal(t, `event Warning ReconcileFailed about MyApp web with message matching "quota"`, e.String())
+
+	e = TestEvent{InvolvedObject: TestEventObject{APIVersion: "apps/v1", Name: "web"}}
+	assert.Equal(t, "event about object web (apps/v1)", e.String())
+}
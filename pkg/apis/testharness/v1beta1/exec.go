@@ -0,0 +1,81 @@
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Validate checks that the pods and the command to execute are specified.
+func (e *TestExec) Validate() error {
+	if err := validatePods(e.Pod, e.Selector); err != nil {
+		return err
+	}
+	if len(e.Command) == 0 {
+		return errors.New("command not specified")
+	}
+	if err := e.Stdout.validate(); err != nil {
+		return fmt.Errorf("stdout: %w", err)
+	}
+	if err := e.Stderr.validate(); err != nil {
+		return fmt.Errorf("stderr: %w", err)
+	}
+	return nil
+}
+
+func (e *TestExec) String() string {
+	return fmt.Sprintf("exec %q in %s", strings.Join(e.Command, " "), podsString(e.Pod, e.Selector, e.Container))
+}
+
+// Validate checks that the pods and at least one expectation on their logs are specified.
+func (l *TestLogs) Validate() error {
+	if err := validatePods(l.Pod, l.Selector); err != nil {
+		return err
+	}
+	if l.OutputMatcher == (OutputMatcher{}) {
+		return errors.New("at least one of contains, equals, regex and subset must be specified")
+	}
+	return l.OutputMatcher.validate()
+}
+
+func (l *TestLogs) String() string {
+	return fmt.Sprintf("logs of %s", podsString(l.Pod, l.Selector, l.Container))
+}
+
+func (m *OutputMatcher) validate() error {
+	if m == nil || m.Regex == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(m.Regex); err != nil {
+		return fmt.Errorf("invalid regex %q: %w", m.Regex, err)
+	}
+	return nil
+}
+
+func validatePods(pod, selector string) error {
+	if (pod == "") == (selector == "") {
+		return errors.New("exactly one of pod and selector must be specified")
+	}
+	if selector != "" {
+		if _, err := labels.Parse(selector); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+	}
+	return nil
+}
+
+func podsString(pod, selector, container string) string {
+	var target string
+	if pod != "" {
+		target = fmt.Sprintf("pod %s", pod)
+	} else {
+		target = fmt.Sprintf("pods with selector %s", selector)
+	}
+	if container != "" {
+		target = fmt.Sprintf("container %s of %s", container, target)
+	}
+	return target
+}
@@ -0,0 +1,63 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestExecValidate(t *testing.T) {
+	for name, tt := range map[string]struct {
+		exec   TestExec
+		errMsg string
+	}{
+		"pod":               {exec: TestExec{Pod: "web-0", Command: []string{"true"}}},
+		"selector":          {exec: TestExec{Selector: "app=web", Container: "web", Command: []string{"cat", "/etc/hostname"}, Stdout: &OutputMatcher{Regex: "^web-"}}},
+		"no command":        {exec: TestExec{Pod: "web-0"}, errMsg: "command not specified"},
+		"pod and selector":  {exec: TestExec{Pod: "web-0", Selector: "app=web", Command: []string{"true"}}, errMsg: "exactly one of pod and selector"},
+		"no pod":            {exec: TestExec{Command: []string{"true"}}, errMsg: "exactly one of pod and selector"},
+		"invalid selector":  {exec: TestExec{Selector: "app in", Command: []string{"true"}}, errMsg: "invalid selector"},
+		"invalid regex":     {exec: TestExec{Pod: "web-0", Command: []string{"true"}, Stderr: &OutputMatcher{Regex: "("}}, errMsg: `stderr: invalid regex "("`},
+		"stdout and stderr": {exec: TestExec{Pod: "web-0", Command: []string{"true"}, Stdout: &OutputMatcher{Equals: new("ok")}, Stderr: &OutputMatcher{Contains: "warning"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tt.exec.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTestLogsValidate(t *testing.T) {
+	for name, tt := range map[string]struct {
+		logs   TestLogs
+		errMsg string
+	}{
+		"contains":       {logs: TestLogs{Pod: "web-0", OutputMatcher: OutputMatcher{Contains: "started"}}},
+		"empty":          {logs: TestLogs{Pod: "web-0", OutputMatcher: OutputMatcher{Equals: new("")}}},
+		"regex":          {logs: TestLogs{Selector: "app=web", Container: "web", OutputMatcher: OutputMatcher{Regex: `listening on :\d+`}}},
+		"no expectation": {logs: TestLogs{Pod: "web-0"}, errMsg: "at least one of contains, equals, regex and subset"},
+		"no pod":         {logs: TestLogs{OutputMatcher: OutputMatcher{Contains: "started"}}, errMsg: "exactly one of pod and selector"},
+		"invalid regex":  {logs: TestLogs{Pod: "web-0", OutputMatcher: OutputMatcher{Regex: "["}}, errMsg: "invalid regex"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tt.logs.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTestExecString(t *testing.T) {
+	e := TestExec{Pod: "web-0", Command: []string{"cat", "/etc/hostname"}}
+	assert.Equal(t, `exec "cat /etc/hostname" in pod web-0`, e.String())
+
+	l := TestLogs{Selector: "app=web", Container: "web"}
+	assert.Equal(t, "logs of container web of pods with selector app=web", l.String())
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -15,11 +17,47 @@ var (
 	errKindNotSpecified  = errors.New("kind not specified")
 	errNameNotSpecified  = errors.New("name not specified")
 	errRefNotSpecified   = errors.New("ref not specified")
+	errNameWithList      = errors.New("name must not be specified for a list")
+	errSelectorNoList    = errors.New("selectors require list to be set")
+	errOptionalWithList  = errors.New("optional must not be specified for a list")
+
+	errBindingNameNotSpecified = errors.New("name not specified")
+	errBindingValueSource      = errors.New("exactly one of jsonPath and celExpr must be specified")
 )
 
 // BuildResourceReference constructs a NamespacedName and unstructured resource from the TestResourceRef.
 func (t *TestResourceRef) BuildResourceReference() (namespacedName types.NamespacedName, referencedResource *unstructured.Unstructured) {
 	referencedResource = &unstructured.Unstructured{}
+	referencedResource.SetGroupVersionKind(t.groupVersionKind())
+
+	namespacedName = types.NamespacedName{
+		Namespace: t.Namespace,
+		Name:      t.Name,
+	}
+
+	return
+}
+
+// BuildResourceListReference constructs an unstructured list and the selectors from the TestResourceRef.
+// The namespace is not part of the returned values.
+func (t *TestResourceRef) BuildResourceListReference() (*unstructured.UnstructuredList, labels.Selector, fields.Selector, error) {
+	list := &unstructured.UnstructuredList{}
+	gvk := t.groupVersionKind()
+	gvk.Kind += "List"
+	list.SetGroupVersionKind(gvk)
+
+	labelSelector, err := labels.Parse(t.LabelSelector)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid label selector %q: %w", t.LabelSelector, err)
+	}
+	fieldSelector, err := fields.ParseSelector(t.FieldSelector)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid field selector %q: %w", t.FieldSelector, err)
+	}
+	return list, labelSelector, fieldSelector, nil
+}
+
+func (t *TestResourceRef) groupVersionKind() schema.GroupVersionKind {
 	apiVersionSplit := strings.Split(t.APIVersion, "/")
 	gvk := schema.GroupVersionKind{
 		Version: apiVersionSplit[len(apiVersionSplit)-1],
@@ -28,14 +66,7 @@ func (t *TestResourceRef) BuildResourceReference() (namespacedName types.Namespa
 	if len(apiVersionSplit) > 1 {
 		gvk.Group = apiVersionSplit[0]
 	}
-	referencedResource.SetGroupVersionKind(gvk)
-
-	namespacedName = types.NamespacedName{
-		Namespace: t.Namespace,
-		Name:      t.Name,
-	}
-
-	return
+	return gvk
 }
 
 // Validate checks that all required fields in TestResourceRef are properly set.
@@ -46,17 +77,40 @@ func (t *TestResourceRef) Validate() error {
 		return errAPIVersionInvalid
 	case t.Kind == "":
 		return errKindNotSpecified
-	case t.Name == "":
+	case t.List && t.Name != "":
+		return errNameWithList
+	case !t.List && t.Name == "":
 		return errNameNotSpecified
+	case !t.List && (t.LabelSelector != "" || t.FieldSelector != ""):
+		return errSelectorNoList
+	case t.List && t.Optional:
+		return errOptionalWithList
 	case t.Ref == "":
 		return errRefNotSpecified
 	}
 
+	if t.List {
+		if _, _, _, err := t.BuildResourceListReference(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (t *TestResourceRef) String() string {
-	return fmt.Sprintf(
+	if t.List {
+		return fmt.Sprintf(
+			"apiVersion=%v, kind=%v, namespace=%v, labelSelector=%v, fieldSelector=%v, ref=%v",
+			t.APIVersion,
+			t.Kind,
+			t.Namespace,
+			t.LabelSelector,
+			t.FieldSelector,
+			t.Ref,
+		)
+	}
+	s := fmt.Sprintf(
 		"apiVersion=%v, kind=%v, namespace=%v, name=%v, ref=%v",
 		t.APIVersion,
 		t.Kind,
@@ -64,4 +118,24 @@ func (t *TestResourceRef) String() string {
 		t.Name,
 		t.Ref,
 	)
+	if t.Optional {
+		s += ", optional=true"
+	}
+	return s
+}
+
+// Validate checks that all required fields in Binding are properly set.
+func (b *Binding) Validate() error {
+	if b.Name == "" {
+		return errBindingNameNotSpecified
+	}
+	if (b.JSONPath == "") == (b.CELExpression == "") {
+		return errBindingValueSource
+	}
+	ref := b.ResourceRef
+	if b.CELExpression == "" && ref.Ref == "" {
+		// The ref is only used to refer to the object in CEL expressions.
+		ref.Ref = b.Name
+	}
+	return ref.Validate()
 }
@@ -95,6 +95,54 @@ func TestValidate(t *testing.T) {
 			errored:       true,
 			expectedError: errRefNotSpecified,
 		},
+		{
+			name: "list with selectors",
+			testResourceRef: TestResourceRef{
+				APIVersion:    "v1",
+				Kind:          "Pod",
+				List:          true,
+				LabelSelector: "app in (a, b)",
+				FieldSelector: "status.phase=Running",
+				Ref:           "pods",
+			},
+			errored: false,
+		},
+		{
+			name: "list with name",
+			testResourceRef: TestResourceRef{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       "test-pod",
+				List:       true,
+				Ref:        "pods",
+			},
+			errored:       true,
+			expectedError: errNameWithList,
+		},
+		{
+			name: "selector without list",
+			testResourceRef: TestResourceRef{
+				APIVersion:    "v1",
+				Kind:          "Pod",
+				Name:          "test-pod",
+				LabelSelector: "app=a",
+				Ref:           "pod",
+			},
+			errored:       true,
+			expectedError: errSelectorNoList,
+		},
+		{
+			name: "optional list",
+			testResourceRef: TestResourceRef{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				List:       true,
+				Optional:   true,
+				Ref:        "pods",
+			},
+			errored:       true,
+			expectedError: errOptionalWithList,
+		},
 		{
 			name: "all attributes are present and valid",
 			testResourceRef: TestResourceRef{
@@ -3,6 +3,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 )
 
@@ -66,10 +67,22 @@ type TestSuite struct {
 	// pulled container images across test runs.
 	KINDNodeCache bool `json:"kindNodeCache"`
 	// Containers to load to each KIND node prior to running the tests.
+	// They are loaded into the nodes of the cluster of any cluster provider.
 	KINDContainers []string `json:"kindContainers"`
+	// ClusterProvider is the name of the provider which starts the cluster for the tests, and deletes it after them.
+	// The built-in providers are "envtest", the same as StartControlPlane, "kind", the same as StartKIND,
+	// and "script", which runs the commands of ScriptCluster.
+	// If not set, and neither StartControlPlane nor StartKIND is set, the tests run against the configured cluster.
+	ClusterProvider string `json:"clusterProvider"`
+	// ClusterProviderConfig is the free-form configuration of a cluster provider which is not built in,
+	// decoded by the provider itself.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ClusterProviderConfig *runtime.RawExtension `json:"clusterProviderConfig,omitempty"`
+	// ScriptCluster configures the commands of the "script" cluster provider.
+	ScriptCluster ScriptCluster `json:"scriptCluster,omitempty"`
 	// If set, do not delete the resources after running the tests (implies SkipClusterDelete).
 	SkipDelete bool `json:"skipDelete"`
-	// If set, do not delete the mocked control plane or kind cluster.
+	// If set, do not delete the mocked control plane, kind cluster, or cluster of the cluster provider.
 	SkipClusterDelete bool `json:"skipClusterDelete"`
 	// Override the default timeout of 30 seconds (in seconds).
 	// +kubebuilder:validation:Format:=int64
@@ -92,21 +105,108 @@ type TestSuite struct {
 	// ReportGranularity defines the granularity at which failures are reported. It defaults to "step".
 	ReportGranularity string `json:"reportGranularity"`
 
+	// LogFormat defines the format of the test output, either "text" or "json". It defaults to "text".
+	// In the "json" format, each record is written as a JSON line with the test case, step, level and time.
+	LogFormat string `json:"logFormat"`
+
+	// LogLevel defines the level up to which logs are written: "error", "warn", "info", "debug" or "trace".
+	// It defaults to "info". At the "debug" level, the requests to the Kubernetes API are logged.
+	LogLevel string `json:"logLevel"`
+
+	// Progress shows a live view of the running test cases, with their current step and elapsed time, and the counts
+	// of passed and failed test cases, when the standard output is a terminal.
+	Progress bool `json:"progress"`
+
 	// Namespace defines the namespace to use for tests
 	// The value "" means to auto-generate tests namespaces, these namespaces will be created and removed for each test
 	// Any other value is the name of the namespace to use.  This namespace will be created if it does not exist and will
 	// be removed it was created (unless --skipDelete is used).
 	Namespace string `json:"namespace"`
-	// Suppress is used to suppress logs
+	// Suppress is used to suppress logs of the given categories: events, commands, diffs and resources.
 	Suppress []string `json:"suppress"`
 
 	// IgnoreFiles is a list of file patterns (e.g., "*.md", "README*") to ignore when collecting test steps.
 	// Files matching these patterns will not generate warnings about not matching the expected test file pattern.
 	IgnoreFiles []string `json:"ignoreFiles"`
 
+	// FailureBundle configures the debug bundle gathered in the artifacts directory when a test case fails.
+	FailureBundle FailureBundle `json:"failureBundle,omitempty"`
+
+	// EventRecording configures the recording of the events of the test namespace while a test case runs.
+	EventRecording EventRecording `json:"eventRecording,omitempty"`
+
+	// Collectors run when any test step of any test case fails, in addition to those of the TestAssert and TestCase.
+	// They collect from the test namespace unless they specify another one.
+	Collectors []*TestCollector `json:"collectors,omitempty"`
+
 	Config *RestConfig `json:"config,omitempty"`
 }
 
+// FailureBundle configures the debug bundle gathered when a test case fails: the objects, events and pod logs
+// of the test namespace, and the status of its workloads which are not ready.
+// It is written to the <artifactsDir>/<suite>/<case>/ directory.
+type FailureBundle struct {
+	// If set, no debug bundle is gathered.
+	Disabled bool `json:"disabled,omitempty"`
+	// Kinds of the objects to dump, as accepted by `kubectl get`, e.g. `deployments.apps`.
+	// Defaults to all the namespaced kinds.
+	Kinds []string `json:"kinds,omitempty"`
+	// Number of lines to collect from the end of the logs of each container. Defaults to all the lines.
+	// +kubebuilder:validation:Format:=int64
+	TailLines int `json:"tailLines,omitempty"`
+}
+
+// ScriptCluster configures the commands which the "script" cluster provider runs to manage a cluster with external
+// tools. The commands run with no timeout unless they set one, and with KUBECONFIG set to the kubeconfig of the cluster
+// once it has been created.
+type ScriptCluster struct {
+	// Create creates the cluster. Required.
+	Create Command `json:"create"`
+	// Kubeconfig writes the kubeconfig of the created cluster to its standard output, which is never logged. Required.
+	Kubeconfig Command `json:"kubeconfig"`
+	// LoadImage loads the container image named by the IMAGE environment variable into the nodes of the cluster.
+	// It runs for each of the KINDContainers.
+	LoadImage Command `json:"loadImage,omitempty"`
+	// CollectLogs writes the logs of the cluster to the directory named by the LOGS_DIR environment variable.
+	CollectLogs Command `json:"collectLogs,omitempty"`
+	// Delete deletes the cluster, unless SkipClusterDelete is set.
+	Delete Command `json:"delete,omitempty"`
+}
+
+// EventRecording configures the recording of the events of the test namespace while a test case runs, so that
+// events which expire before the end of the test case are not lost. The recorded events are tagged with the test step
+// which was running, listed in the log at the end of the test case, and written to the events-timeline.log file
+// in the <artifactsDir>/<suite>/<case>/ directory.
+type EventRecording struct {
+	// If set, events are not recorded; they are only listed at the end of the test case.
+	Disabled bool `json:"disabled,omitempty"`
+	// Types of the events to record, e.g. Warning. Defaults to all the types.
+	Types []string `json:"types,omitempty"`
+	// Reasons of the events to record, e.g. FailedScheduling. Defaults to all the reasons.
+	Reasons []string `json:"reasons,omitempty"`
+	// Kinds of the objects involved in the events to record, e.g. Pod. Defaults to all the kinds.
+	InvolvedKinds []string `json:"involvedKinds,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TestCase contains settings which apply to a whole test case.
+// It is read from the kuttl-case.yaml file in the test case directory.
+type TestCase struct {
+	// The type meta object, should always be a GVK of kuttl.dev/v1beta1/TestCase.
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata, which is not used by KUTTL.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Matrix maps template variable names to lists of values. The test case is run once for each
+	// combination of values, with the values of that combination available as template variables.
+	Matrix map[string][]string `json:"matrix,omitempty"`
+
+	// Collectors run when any test step of the test case fails, in addition to those of the TestAssert
+	// and before those of the TestSuite. They collect from the test namespace unless they specify another one.
+	Collectors []*TestCollector `json:"collectors,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TestStep contains settings to apply to a test step.
@@ -148,6 +248,16 @@ type TestStep struct {
 
 	// Specifies the context to use from the Kubeconfig.
 	Context string `json:"context,omitempty"`
+
+	// Bindings to evaluate once the step has succeeded, storing values from cluster objects in test case variables.
+	Bindings []Binding `json:"bindings,omitempty"`
+
+	// States of cluster objects to wait for, in order, after the step's objects are applied and before its
+	// assertions are checked.
+	Wait []TestWait `json:"wait,omitempty"`
+
+	// Commands to execute in the containers of pods, in order, after the waits and before the assertions are checked.
+	Exec []TestExec `json:"exec,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -170,6 +280,13 @@ type TestAssert struct {
 
 	AssertAny []*Assertion `json:"assertAny,omitempty"`
 	AssertAll []*Assertion `json:"assertAll,omitempty"`
+
+	// Exec is a set of commands executed in the containers of pods as assertions for the current step.
+	Exec []TestExec `json:"exec,omitempty"`
+	// Logs is a set of expectations on the logs of pods for the current step.
+	Logs []TestLogs `json:"logs,omitempty"`
+	// Events is a set of expectations on the Kubernetes events emitted by the time the current step ends.
+	Events []TestEvent `json:"events,omitempty"`
 }
 
 // TestAssertCommand an assertion based on the result of the execution of a command.
@@ -184,6 +301,28 @@ type TestAssertCommand struct {
 	Script string `json:"script"`
 	// If set, the output from the command is NOT logged.  Useful for sensitive logs or to reduce noise.
 	SkipLogOutput bool `json:"skipLogOutput"`
+	// The exit code the command is expected to return. Defaults to 0.
+	ExitCode *int `json:"exitCode,omitempty"`
+	// Expectations on the standard output of the command.
+	Stdout *OutputMatcher `json:"stdout,omitempty"`
+	// Expectations on the standard error of the command.
+	Stderr *OutputMatcher `json:"stderr,omitempty"`
+	// Limit a single run of this command to a number of seconds, within the remaining TestAssert timeout.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// OutputMatcher describes expectations on the output of a command. All the fields which are set must match.
+type OutputMatcher struct {
+	// The output must contain this string.
+	Contains string `json:"contains,omitempty"`
+	// The output must be equal to this string, ignoring surrounding whitespace.
+	// An empty string asserts that the output is empty.
+	Equals *string `json:"equals,omitempty"`
+	// The output must match this regular expression (RE2 syntax).
+	Regex string `json:"regex,omitempty"`
+	// The output, parsed as JSON or YAML, must contain this YAML document,
+	// in the same way as objects in assert files are matched.
+	Subset string `json:"subset,omitempty"`
 }
 
 // ObjectReference is a Kubernetes object reference with added labels to allow referencing
@@ -213,15 +352,23 @@ type Command struct {
 	Timeout int `json:"timeout"`
 	// If set, the output from the command is NOT logged.  Useful for sensitive logs or to reduce noise.
 	SkipLogOutput bool `json:"skipLogOutput"`
+	// If set, the standard output of the command (with surrounding whitespace trimmed) is stored in the test case
+	// variable with this name. It is available to the templates, commands and assertions of the following steps.
+	// Not supported for background commands or TestSuite commands.
+	OutputVar string `json:"outputVar,omitempty"`
+	// If set, the standard output of the command is parsed as JSON or YAML, and the result of evaluating
+	// this kubectl-style JSONPath expression (e.g. `{.status.loadBalancer.ingress[0].ip}`) is stored instead.
+	// Requires outputVar.
+	OutputJSONPath string `json:"outputJSONPath,omitempty"`
 }
 
 // TestCollector are post assert / error commands that allow for the collection of information sent to the test log.
-// Type can be pod, command or event.  For backward compatibility, pod is default and doesn't need to be specified
+// Type can be pod, command, events or resources.  For backward compatibility, pod is default and doesn't need to be specified
 // For pod, At least one of `pod` or `selector` is required.
 // For command, Command must be specified and Type can be == "command" but no other fields are valid
 // For event, Type must be == "events" and Namespace and Name can be specified, if no ns or name, the default events are provided.  If no name, than all events for that ns are provided.
 type TestCollector struct {
-	// Type is a collector type which is pod, command or events
+	// Type is a collector type which is pod, command, events or resources
 	// command is default type if command field is not empty
 	// misconfiguration will lead to warning message in the logs
 	Type string `json:"type,omitempty"`
@@ -239,6 +386,107 @@ type TestCollector struct {
 	Tail int `json:"tail,omitempty"`
 	// Cmd is a command to run for collection.  It requires an empty Type or Type=command
 	Cmd string `json:"command,omitempty"`
+	// Kinds are the types of the objects to dump for the resources type, as accepted by `kubectl get`,
+	// e.g. `deployments.apps` or `ConfigMap`. All the namespaced kinds are dumped by default.
+	Kinds []string `json:"kinds,omitempty"`
+	// When is the outcome of the step after which the collector runs: failure (default), success or always.
+	When string `json:"when,omitempty"`
+}
+
+// TestWait describes a state of cluster objects to wait for.
+// The objects are selected by name or by label selector; all the selected objects must reach the state.
+type TestWait struct {
+	// Type of the state to wait for: condition, rollout, deletion, job or logs.
+	Type string `json:"type"`
+	// apiVersion of the objects. Defaults to apps/v1 for rollout, batch/v1 for job and v1 for logs.
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind of the objects. Defaults to Job for job and Pod for logs.
+	Kind string `json:"kind,omitempty"`
+	// Namespace of the objects. The current test namespace will be used by default.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object. Exactly one of name and selector must be set.
+	Name string `json:"name,omitempty"`
+	// A label query selecting the objects.
+	Selector string `json:"selector,omitempty"`
+	// The type of the status condition to wait for. Required for the condition type.
+	Condition string `json:"condition,omitempty"`
+	// The status the condition must have. Defaults to "True".
+	Status string `json:"status,omitempty"`
+	// The string the pod logs must contain. Required for the logs type.
+	Contains string `json:"contains,omitempty"`
+	// The container whose logs to check. All the containers of the pod are checked by default.
+	Container string `json:"container,omitempty"`
+	// Limit this wait to a number of seconds, within the step timeout which the waits share with the assertions.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// TestExec describes a command to execute in a container of pods, without relying on a kubectl binary.
+// The pods are selected by name or by label selector; the command is executed in all the selected pods.
+type TestExec struct {
+	// Namespace of the pods. The current test namespace will be used by default.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the pod. Exactly one of pod and selector must be set.
+	Pod string `json:"pod,omitempty"`
+	// A label query selecting the pods.
+	Selector string `json:"selector,omitempty"`
+	// The container to execute the command in. Defaults to the kubectl.kubernetes.io/default-container
+	// annotation of the pod, or its first container.
+	Container string `json:"container,omitempty"`
+	// The command and its arguments. It is not run in a shell.
+	Command []string `json:"command"`
+	// The exit code the command is expected to return. Defaults to 0.
+	ExitCode *int `json:"exitCode,omitempty"`
+	// Expectations on the standard output of the command.
+	Stdout *OutputMatcher `json:"stdout,omitempty"`
+	// Expectations on the standard error of the command.
+	Stderr *OutputMatcher `json:"stderr,omitempty"`
+	// If set, the output from the command is NOT logged.  Useful for sensitive logs or to reduce noise.
+	SkipLogOutput bool `json:"skipLogOutput,omitempty"`
+	// Limit a single run of this command to a number of seconds, within the step timeout, or the remaining
+	// TestAssert timeout.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// TestLogs describes expectations on the logs of pods. All the fields of the matcher which are set must match
+// the logs of each of the selected pods.
+type TestLogs struct {
+	// Namespace of the pods. The current test namespace will be used by default.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the pod. Exactly one of pod and selector must be set.
+	Pod string `json:"pod,omitempty"`
+	// A label query selecting the pods.
+	Selector string `json:"selector,omitempty"`
+	// The container whose logs to check. The logs of all the containers of the pod are concatenated by default.
+	Container string `json:"container,omitempty"`
+
+	OutputMatcher `json:",inline"`
+}
+
+// TestEvent describes Kubernetes events which must have been emitted. The fields which are set must all match
+// the events, which must have occurred at least minCount times in total.
+type TestEvent struct {
+	// Namespace of the events. The current test namespace will be used by default.
+	Namespace string `json:"namespace,omitempty"`
+	// The object the events are about.
+	InvolvedObject TestEventObject `json:"involvedObject,omitempty"`
+	// Type of the events, e.g. Warning.
+	Type string `json:"type,omitempty"`
+	// Reason of the events, e.g. ReconcileFailed.
+	Reason string `json:"reason,omitempty"`
+	// A regular expression (RE2 syntax) which the message of the events must match.
+	Message string `json:"message,omitempty"`
+	// The minimum number of occurrences of the matching events. Defaults to 1.
+	MinCount int `json:"minCount,omitempty"`
+}
+
+// TestEventObject identifies the object Kubernetes events are about. The fields which are set must match.
+type TestEventObject struct {
+	// apiVersion of the object, e.g. apps/v1.
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind of the object, e.g. Deployment.
+	Kind string `json:"kind,omitempty"`
+	// Name of the object.
+	Name string `json:"name,omitempty"`
 }
 
 // TestResourceRef defines a reference to a Kubernetes resource for testing.
@@ -248,6 +496,29 @@ type TestResourceRef struct {
 	Namespace  string `json:"namespace,omitempty"`
 	Name       string `json:"name,omitempty"`
 	Ref        string `json:"ref,omitempty"`
+	// If set, the reference resolves to the list of all objects of the kind in the namespace
+	// matching the selectors, instead of a single named object. Name must not be set.
+	List bool `json:"list,omitempty"`
+	// A label query to filter the listed objects. Requires list.
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// A field query to filter the listed objects. Requires list.
+	FieldSelector string `json:"fieldSelector,omitempty"`
+	// If set, a missing object resolves to null instead of failing the assertion or binding.
+	// Must not be set for a list.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// Binding defines a test case variable whose value is taken from a cluster object.
+// Exactly one of JSONPath and CELExpression must be set.
+type Binding struct {
+	// Name of the test case variable to store the value in.
+	Name string `json:"name"`
+	// The object to take the value from. Its ref is only required when using a CEL expression.
+	ResourceRef TestResourceRef `json:"resourceRef"`
+	// A kubectl-style JSONPath expression, e.g. `{.spec.clusterIP}`, evaluated against the object.
+	JSONPath string `json:"jsonPath,omitempty"`
+	// A CEL expression evaluated with the object bound to its ref.
+	CELExpression string `json:"celExpr,omitempty"`
 }
 
 // Assertion defines a test assertion using CEL expressions.
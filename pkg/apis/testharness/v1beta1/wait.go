@@ -0,0 +1,144 @@
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Types of TestWait.
+const (
+	WaitTypeCondition = "condition"
+	WaitTypeRollout   = "rollout"
+	WaitTypeDeletion  = "deletion"
+	WaitTypeJob       = "job"
+	WaitTypeLogs      = "logs"
+)
+
+var (
+	waitTypes    = []string{WaitTypeCondition, WaitTypeRollout, WaitTypeDeletion, WaitTypeJob, WaitTypeLogs}
+	rolloutKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+)
+
+// Validate checks that the fields required by the type of the wait are set, and only those.
+func (w *TestWait) Validate() error {
+	if !slices.Contains(waitTypes, w.Type) {
+		return fmt.Errorf("wait type %q unknown", w.Type)
+	}
+	if (w.Name == "") == (w.Selector == "") {
+		return errors.New("exactly one of name and selector must be specified")
+	}
+	if w.Selector != "" {
+		if _, err := labels.Parse(w.Selector); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", w.Selector, err)
+		}
+	}
+	if w.Type != WaitTypeCondition && (w.Condition != "" || w.Status != "") {
+		return fmt.Errorf("condition and status are only valid for the %s type", WaitTypeCondition)
+	}
+	if w.Type != WaitTypeLogs && (w.Contains != "" || w.Container != "") {
+		return fmt.Errorf("contains and container are only valid for the %s type", WaitTypeLogs)
+	}
+
+	gvk, err := w.GroupVersionKind()
+	if err != nil {
+		return err
+	}
+
+	switch w.Type {
+	case WaitTypeCondition:
+		if w.Condition == "" {
+			return errors.New("condition wait requires a condition")
+		}
+	case WaitTypeRollout:
+		if gvk.Group != "apps" || !slices.Contains(rolloutKinds, gvk.Kind) {
+			return fmt.Errorf("rollout wait requires an apps Deployment, StatefulSet or DaemonSet, got %s", gvk)
+		}
+	case WaitTypeDeletion:
+	case WaitTypeJob:
+		if gvk.Group != "batch" || gvk.Kind != "Job" {
+			return fmt.Errorf("job wait requires a batch Job, got %s", gvk)
+		}
+	case WaitTypeLogs:
+		if gvk.Group != "" || gvk.Kind != "Pod" {
+			return fmt.Errorf("logs wait requires a Pod, got %s", gvk)
+		}
+		if w.Contains == "" {
+			return errors.New("logs wait requires contains")
+		}
+	}
+	return nil
+}
+
+// GroupVersionKind returns the kind of the objects to wait for, applying the defaults of the type of the wait.
+func (w *TestWait) GroupVersionKind() (schema.GroupVersionKind, error) {
+	apiVersion, kind := w.APIVersion, w.Kind
+	switch w.Type {
+	case WaitTypeRollout:
+		if apiVersion == "" {
+			apiVersion = "apps/v1"
+		}
+	case WaitTypeJob:
+		if apiVersion == "" {
+			apiVersion = "batch/v1"
+		}
+		if kind == "" {
+			kind = "Job"
+		}
+	case WaitTypeLogs:
+		if apiVersion == "" {
+			apiVersion = "v1"
+		}
+		if kind == "" {
+			kind = "Pod"
+		}
+	}
+
+	if apiVersion == "" {
+		return schema.GroupVersionKind{}, errors.New("apiVersion not specified")
+	}
+	if kind == "" {
+		return schema.GroupVersionKind{}, errKindNotSpecified
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+	return gv.WithKind(kind), nil
+}
+
+func (w *TestWait) String() string {
+	var target string
+	if gvk, err := w.GroupVersionKind(); err == nil {
+		target = gvk.Kind
+	}
+	if w.Name != "" {
+		target = fmt.Sprintf("%s %s", target, w.Name)
+	} else {
+		target = fmt.Sprintf("%s with selector %s", target, w.Selector)
+	}
+	target = strings.TrimSpace(target)
+
+	switch w.Type {
+	case WaitTypeCondition:
+		status := w.Status
+		if status == "" {
+			status = "True"
+		}
+		return fmt.Sprintf("condition %s=%s of %s", w.Condition, status, target)
+	case WaitTypeRollout:
+		return fmt.Sprintf("rollout of %s", target)
+	case WaitTypeDeletion:
+		return fmt.Sprintf("deletion of %s", target)
+	case WaitTypeJob:
+		return fmt.Sprintf("completion of %s", target)
+	case WaitTypeLogs:
+		return fmt.Sprintf("logs of %s to contain %q", target, w.Contains)
+	default:
+		return fmt.Sprintf("%s of %s", w.Type, target)
+	}
+}
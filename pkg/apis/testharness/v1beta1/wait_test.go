@@ -0,0 +1,50 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestWaitValidate(t *testing.T) {
+	for name, tt := range map[string]struct {
+		wait   TestWait
+		errMsg string
+	}{
+		"condition":                {wait: TestWait{Type: WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Name: "web", Condition: "Ready"}},
+		"condition with selector":  {wait: TestWait{Type: WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Selector: "app=web", Condition: "Ready", Status: "False"}},
+		"rollout":                  {wait: TestWait{Type: WaitTypeRollout, Kind: "StatefulSet", Name: "db"}},
+		"deletion":                 {wait: TestWait{Type: WaitTypeDeletion, APIVersion: "v1", Kind: "ConfigMap", Name: "config"}},
+		"job":                      {wait: TestWait{Type: WaitTypeJob, Name: "migrate"}},
+		"logs":                     {wait: TestWait{Type: WaitTypeLogs, Selector: "app=web", Contains: "started", Container: "web"}},
+		"unknown type":             {wait: TestWait{Type: "ready", Name: "web"}, errMsg: `wait type "ready" unknown`},
+		"name and selector":        {wait: TestWait{Type: WaitTypeJob, Name: "migrate", Selector: "app=web"}, errMsg: "exactly one of name and selector"},
+		"no name nor selector":     {wait: TestWait{Type: WaitTypeJob}, errMsg: "exactly one of name and selector"},
+		"invalid selector":         {wait: TestWait{Type: WaitTypeJob, Selector: "app in"}, errMsg: "invalid selector"},
+		"condition missing":        {wait: TestWait{Type: WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Name: "web"}, errMsg: "requires a condition"},
+		"condition without kind":   {wait: TestWait{Type: WaitTypeCondition, APIVersion: "v1", Name: "web", Condition: "Ready"}, errMsg: "kind not specified"},
+		"rollout of a pod":         {wait: TestWait{Type: WaitTypeRollout, APIVersion: "v1", Kind: "Pod", Name: "web"}, errMsg: "rollout wait requires"},
+		"job of another kind":      {wait: TestWait{Type: WaitTypeJob, Kind: "CronJob", Name: "web"}, errMsg: "job wait requires a batch Job"},
+		"logs without contains":    {wait: TestWait{Type: WaitTypeLogs, Name: "web"}, errMsg: "logs wait requires contains"},
+		"contains for a condition": {wait: TestWait{Type: WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Name: "web", Condition: "Ready", Contains: "x"}, errMsg: "only valid for the logs type"},
+		"status for a deletion":    {wait: TestWait{Type: WaitTypeDeletion, APIVersion: "v1", Kind: "Pod", Name: "web", Status: "True"}, errMsg: "only valid for the condition type"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tt.wait.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTestWaitString(t *testing.T) {
+	assert.Equal(t, "condition Ready=True of Pod web",
+		(&TestWait{Type: WaitTypeCondition, APIVersion: "v1", Kind: "Pod", Name: "web", Condition: "Ready"}).String())
+	assert.Equal(t, "rollout of Deployment with selector app=web",
+		(&TestWait{Type: WaitTypeRollout, Kind: "Deployment", Selector: "app=web"}).String())
+	assert.Equal(t, `logs of Pod web to contain "started"`,
+		(&TestWait{Type: WaitTypeLogs, Name: "web", Contains: "started"}).String())
+}
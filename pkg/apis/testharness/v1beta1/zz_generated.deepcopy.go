@@ -40,6 +40,23 @@ func (in *Assertion) DeepCopy() *Assertion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Binding) DeepCopyInto(out *Binding) {
+	*out = *in
+	out.ResourceRef = in.ResourceRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Binding.
+func (in *Binding) DeepCopy() *Binding {
+	if in == nil {
+		return nil
+	}
+	out := new(Binding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Command) DeepCopyInto(out *Command) {
 	*out = *in
@@ -56,6 +73,58 @@ func (in *Command) DeepCopy() *Command {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRecording) DeepCopyInto(out *EventRecording) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InvolvedKinds != nil {
+		in, out := &in.InvolvedKinds, &out.InvolvedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRecording.
+func (in *EventRecording) DeepCopy() *EventRecording {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRecording)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureBundle) DeepCopyInto(out *FailureBundle) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureBundle.
+func (in *FailureBundle) DeepCopy() *FailureBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -80,6 +149,27 @@ func (in *ObjectReference) DeepCopy() *ObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputMatcher) DeepCopyInto(out *OutputMatcher) {
+	*out = *in
+	if in.Equals != nil {
+		in, out := &in.Equals, &out.Equals
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputMatcher.
+func (in *OutputMatcher) DeepCopy() *OutputMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestConfig.
 func (in *RestConfig) DeepCopy() *RestConfig {
 	if in == nil {
@@ -90,6 +180,27 @@ func (in *RestConfig) DeepCopy() *RestConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptCluster) DeepCopyInto(out *ScriptCluster) {
+	*out = *in
+	out.Create = in.Create
+	out.Kubeconfig = in.Kubeconfig
+	out.LoadImage = in.LoadImage
+	out.CollectLogs = in.CollectLogs
+	out.Delete = in.Delete
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptCluster.
+func (in *ScriptCluster) DeepCopy() *ScriptCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestAssert) DeepCopyInto(out *TestAssert) {
 	*out = *in
@@ -102,14 +213,16 @@ func (in *TestAssert) DeepCopyInto(out *TestAssert) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(TestCollector)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
 	if in.Commands != nil {
 		in, out := &in.Commands, &out.Commands
 		*out = make([]TestAssertCommand, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ResourceRefs != nil {
 		in, out := &in.ResourceRefs, &out.ResourceRefs
@@ -138,6 +251,23 @@ func (in *TestAssert) DeepCopyInto(out *TestAssert) {
 			}
 		}
 	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = make([]TestExec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = make([]TestLogs, len(*in))
+		copy(*out, *in)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]TestEvent, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -162,6 +292,21 @@ func (in *TestAssert) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestAssertCommand) DeepCopyInto(out *TestAssertCommand) {
 	*out = *in
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int)
+		**out = **in
+	}
+	if in.Stdout != nil {
+		in, out := &in.Stdout, &out.Stdout
+		*out = new(OutputMatcher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stderr != nil {
+		in, out := &in.Stderr, &out.Stderr
+		*out = new(OutputMatcher)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -175,9 +320,67 @@ func (in *TestAssertCommand) DeepCopy() *TestAssertCommand {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestCase) DeepCopyInto(out *TestCase) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Collectors != nil {
+		in, out := &in.Collectors, &out.Collectors
+		*out = make([]*TestCollector, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TestCollector)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestCase.
+func (in *TestCase) DeepCopy() *TestCase {
+	if in == nil {
+		return nil
+	}
+	out := new(TestCase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestCase) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestCollector) DeepCopyInto(out *TestCollector) {
 	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -191,6 +394,75 @@ func (in *TestCollector) DeepCopy() *TestCollector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestEvent) DeepCopyInto(out *TestEvent) {
+	*out = *in
+	out.InvolvedObject = in.InvolvedObject
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestEvent.
+func (in *TestEvent) DeepCopy() *TestEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(TestEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestEventObject) DeepCopyInto(out *TestEventObject) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestEventObject.
+func (in *TestEventObject) DeepCopy() *TestEventObject {
+	if in == nil {
+		return nil
+	}
+	out := new(TestEventObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestExec) DeepCopyInto(out *TestExec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int)
+		**out = **in
+	}
+	if in.Stdout != nil {
+		in, out := &in.Stdout, &out.Stdout
+		*out = new(OutputMatcher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stderr != nil {
+		in, out := &in.Stderr, &out.Stderr
+		*out = new(OutputMatcher)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestExec.
+func (in *TestExec) DeepCopy() *TestExec {
+	if in == nil {
+		return nil
+	}
+	out := new(TestExec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestFile) DeepCopyInto(out *TestFile) {
 	*out = *in
@@ -222,6 +494,23 @@ func (in *TestFile) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestLogs) DeepCopyInto(out *TestLogs) {
+	*out = *in
+	in.OutputMatcher.DeepCopyInto(&out.OutputMatcher)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestLogs.
+func (in *TestLogs) DeepCopy() *TestLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(TestLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestResourceRef) DeepCopyInto(out *TestResourceRef) {
 	*out = *in
@@ -270,6 +559,23 @@ func (in *TestStep) DeepCopyInto(out *TestStep) {
 		*out = make([]Command, len(*in))
 		copy(*out, *in)
 	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]Binding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = make([]TestWait, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = make([]TestExec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -331,6 +637,25 @@ func (in *TestSuite) DeepCopyInto(out *TestSuite) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ClusterProviderConfig != nil {
+		in, out := &in.ClusterProviderConfig, &out.ClusterProviderConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ScriptCluster = in.ScriptCluster
+	in.FailureBundle.DeepCopyInto(&out.FailureBundle)
+	in.EventRecording.DeepCopyInto(&out.EventRecording)
+	if in.Collectors != nil {
+		in, out := &in.Collectors, &out.Collectors
+		*out = make([]*TestCollector, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TestCollector)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -355,3 +680,19 @@ func (in *TestSuite) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestWait) DeepCopyInto(out *TestWait) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestWait.
+func (in *TestWait) DeepCopy() *TestWait {
+	if in == nil {
+		return nil
+	}
+	out := new(TestWait)
+	in.DeepCopyInto(out)
+	return out
+}
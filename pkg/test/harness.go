@@ -1,10 +1,36 @@
 // Package test provides a public API for KUTTL test harness functionality.
 package test
 
-import "github.com/kudobuilder/kuttl/internal/harness"
+import (
+	"github.com/kudobuilder/kuttl/internal/cluster"
+	"github.com/kudobuilder/kuttl/internal/harness"
+	testutils "github.com/kudobuilder/kuttl/internal/utils"
+)
 
 // This type alias is here to avoid breaking the only apparent active user of kuttl Go API,
 // https://github.com/kube-green/kube-green/blob/main/tests/integration/kuttl_test.go
 
 // Harness provides a type alias for harness.Harness to maintain backward compatibility.
 type Harness = harness.Harness
+
+// ClusterProvider provides the cluster the tests run against. See RegisterClusterProvider.
+type ClusterProvider = cluster.Provider
+
+// ClusterProviderOptions are the options of a ClusterProvider which do not come from the test suite.
+// The provider-specific configuration comes from the clusterProviderConfig field of the TestSuite.
+type ClusterProviderOptions = cluster.Options
+
+// Logger is the logger of the test harness, passed to a ClusterProvider in its ClusterProviderOptions.
+type Logger = testutils.Logger
+
+// DockerClient is the client of the Docker daemon, passed to a ClusterProvider in its ClusterProviderOptions.
+type DockerClient = testutils.DockerClient
+
+// ClusterProviderFactory creates a ClusterProvider configured by the test suite.
+type ClusterProviderFactory = cluster.Factory
+
+// RegisterClusterProvider makes a cluster provider available by name, for the clusterProvider field of the TestSuite.
+// It panics if a provider is already registered with the same name.
+func RegisterClusterProvider(name string, factory ClusterProviderFactory) {
+	cluster.Register(name, factory)
+}